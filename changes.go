@@ -0,0 +1,60 @@
+package configlite
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChangeEvent reports that a single configuration key was inserted, updated
+// or deleted. Version is drawn from a single counter shared by every key in
+// config_changes, so it strictly increases on every change, including across
+// process restarts and regardless of which process or connection made the
+// change, and can be used as a per-application cursor into config_changes.
+type ChangeEvent struct {
+	Application string
+	Key         string
+	Version     int64
+	UpdatedAt   time.Time
+}
+
+// PollChanges returns, in version order, every change recorded for app
+// since afterVersion (exclusive), along with the highest version returned.
+// Callers such as configliteserver's change-notification hub use it to
+// detect writes made by other processes sharing the same database file.
+func (r *Repository) PollChanges(app string, afterVersion int64) ([]ChangeEvent, int64, error) {
+	rows, err := r.db.Query(`
+		SELECT configuration_name, version, updated_at
+		FROM config_changes
+		WHERE application_name = ?
+			AND version > ?
+		ORDER BY version ASC`, app, afterVersion)
+	if err != nil {
+		return nil, afterVersion, fmt.Errorf("cannot poll changes for %s: %w", app, err)
+	}
+	defer rows.Close()
+
+	maxVersion := afterVersion
+	var events []ChangeEvent
+	for rows.Next() {
+		var key string
+		var version int64
+		var updatedAt time.Time
+		if err := rows.Scan(&key, &version, &updatedAt); err != nil {
+			return nil, afterVersion, fmt.Errorf("cannot scan change row for %s: %w", app, err)
+		}
+		events = append(events, ChangeEvent{
+			Application: app,
+			Key:         key,
+			Version:     version,
+			UpdatedAt:   updatedAt,
+		})
+		if version > maxVersion {
+			maxVersion = version
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, afterVersion, fmt.Errorf("cannot iterate changes for %s: %w", app, err)
+	}
+
+	return events, maxVersion, nil
+}
@@ -0,0 +1,149 @@
+package configlite
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+)
+
+// AESKeyfileCipher is a Cipher backed by a single AES-256-GCM key read from
+// a local file. It is the simplest backend: anyone with read access to the
+// key file can decrypt every secret encrypted with it.
+type AESKeyfileCipher struct {
+	key []byte
+}
+
+// NewAESKeyfileCipher loads a 32-byte AES-256 key from path.
+func NewAESKeyfileCipher(path string) (*AESKeyfileCipher, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read AES key file %s: %w", path, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("AES key file %s must contain exactly 32 bytes, got %d", path, len(key))
+	}
+	return &AESKeyfileCipher{key: key}, nil
+}
+
+func (c *AESKeyfileCipher) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build AES cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt implements Cipher. keyID is ignored: this backend always
+// encrypts with its single configured key.
+func (c *AESKeyfileCipher) Encrypt(plaintext []byte, keyID string) ([]byte, error) {
+	gcm, err := c.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt implements Decrypter.
+func (c *AESKeyfileCipher) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	gcm, err := c.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext shorter than the AES-GCM nonce")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// AgeCipher is a Cipher backed by an age (https://age-encryption.org)
+// recipient/identity pair. Encrypt only needs the recipient; Decrypt only
+// needs the identity, so either may be left unset depending on whether this
+// Repository only ever writes or only ever reads a given secret.
+type AgeCipher struct {
+	recipient age.Recipient
+	identity  age.Identity
+}
+
+// NewAgeCipher builds an AgeCipher from a recipient string (as printed by
+// `age-keygen`, starting with "age1...") and/or an identity string (starting
+// with "AGE-SECRET-KEY-"). Either argument may be empty.
+func NewAgeCipher(recipient, identity string) (*AgeCipher, error) {
+	var c AgeCipher
+
+	if recipient != "" {
+		r, err := age.ParseX25519Recipient(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse age recipient: %w", err)
+		}
+		c.recipient = r
+	}
+
+	if identity != "" {
+		id, err := age.ParseX25519Identity(identity)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse age identity: %w", err)
+		}
+		c.identity = id
+	}
+
+	return &c, nil
+}
+
+// Encrypt implements Cipher. keyID is ignored: the recipient to encrypt for
+// is fixed at construction time.
+func (c *AgeCipher) Encrypt(plaintext []byte, keyID string) ([]byte, error) {
+	if c.recipient == nil {
+		return nil, fmt.Errorf("age cipher has no recipient configured for encryption")
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, c.recipient)
+	if err != nil {
+		return nil, fmt.Errorf("cannot start age encryption: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("cannot write plaintext to age encryptor: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("cannot finalize age encryption: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decrypt implements Decrypter.
+func (c *AgeCipher) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	if c.identity == nil {
+		return nil, fmt.Errorf("age cipher has no identity configured for decryption")
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), c.identity)
+	if err != nil {
+		return nil, fmt.Errorf("cannot start age decryption: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read age-decrypted plaintext: %w", err)
+	}
+	return plaintext, nil
+}
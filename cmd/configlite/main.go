@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/dgsb/configlite"
+	"github.com/dgsb/configlite/configliteserver"
 
 	"github.com/alecthomas/kong"
 )
@@ -100,12 +107,276 @@ func (cmd *DeleteConfigCmd) Run() error {
 	return cmd.GetRepo().DeleteConfig(cmd.Application, cmd.Configuration, cmd.LikePattern)
 }
 
+type ValidateCmd struct {
+	CommonConfig `embed:""`
+	Application  string `arg:"" description:"the application whose configuration has to be validated"`
+}
+
+func (cmd *ValidateCmd) Run() error {
+	repo, err := configlite.New(cmd.Database)
+	if err != nil {
+		return fmt.Errorf("cannot open configuration database: %w", err)
+	}
+
+	violations, err := repo.Validate(cmd.Application)
+	if err != nil {
+		return fmt.Errorf("cannot validate configuration for application %s: %w", cmd.Application, err)
+	}
+
+	for _, v := range violations {
+		fmt.Println(v.Error())
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d configuration value(s) violate the schema for %s", len(violations), cmd.Application)
+	}
+	return nil
+}
+
+type ExportCmd struct {
+	CommonConfig `embed:""`
+	Format       string   `short:"f" default:"json" enum:"json,yaml" description:"the format to export configurations in"`
+	Output       string   `short:"o" default:"-" description:"file to write the export to, - for stdout"`
+	Applications []string `arg:"" optional:"" description:"applications to export; every application with stored configurations if omitted"`
+}
+
+func (cmd *ExportCmd) Run() error {
+	repo, err := configlite.New(cmd.Database)
+	if err != nil {
+		return fmt.Errorf("cannot open configuration database: %w", err)
+	}
+
+	w := os.Stdout
+	if cmd.Output != "-" {
+		w, err = os.Create(cmd.Output)
+		if err != nil {
+			return fmt.Errorf("cannot create export file %s: %w", cmd.Output, err)
+		}
+		defer w.Close()
+	}
+
+	return repo.Export(w, cmd.Format, cmd.Applications...)
+}
+
+type ImportCmd struct {
+	CommonConfig `embed:""`
+	Format       string `short:"f" default:"json" enum:"json,yaml" description:"the format of the document being imported"`
+	Mode         string `long:"mode" default:"merge" enum:"merge,replace,dry-run" description:"how to reconcile the document with existing configurations"`
+	Input        string `arg:"" default:"-" description:"file to read the import from, - for stdin"`
+}
+
+var importModes = map[string]configlite.ImportMode{
+	"merge":   configlite.Merge,
+	"replace": configlite.Replace,
+	"dry-run": configlite.DryRun,
+}
+
+func (cmd *ImportCmd) Run() error {
+	repo, err := configlite.New(cmd.Database)
+	if err != nil {
+		return fmt.Errorf("cannot open configuration database: %w", err)
+	}
+
+	r := os.Stdin
+	if cmd.Input != "-" {
+		r, err = os.Open(cmd.Input)
+		if err != nil {
+			return fmt.Errorf("cannot open import file %s: %w", cmd.Input, err)
+		}
+		defer r.Close()
+	}
+
+	report, err := repo.Import(r, cmd.Format, importModes[cmd.Mode])
+	if err != nil {
+		return fmt.Errorf("cannot import configurations: %w", err)
+	}
+
+	if importModes[cmd.Mode] == configlite.DryRun {
+		jsonReport, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json formatting import report: %w", err)
+		}
+		fmt.Println(string(jsonReport))
+	}
+	return nil
+}
+
+type ResolveCmd struct {
+	CommonConfig `embed:""`
+	Profile      string `long:"profile" description:"named profile overlay to consult, e.g. prod"`
+	Format       string `short:"f" default:"json" enum:"json,text" description:"the format to display the resolved configuration in"`
+	Scope        string `arg:"" description:"the scope to resolve, e.g. app/prod/host-a"`
+}
+
+func (cmd *ResolveCmd) Run() error {
+	repo, err := configlite.New(cmd.Database)
+	if err != nil {
+		return fmt.Errorf("cannot open configuration database: %w", err)
+	}
+
+	var opts []configlite.ConfigOption
+	if cmd.Profile != "" {
+		opts = append(opts, configlite.WithProfile(cmd.Profile))
+	}
+
+	effective, err := repo.GetEffectiveConfigs(cmd.Scope, opts...)
+	if err != nil {
+		return fmt.Errorf("cannot resolve scope %s: %w", cmd.Scope, err)
+	}
+
+	switch cmd.Format {
+	case "json":
+		jsonEffective, err := json.MarshalIndent(effective, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json formatting resolved configs: %w", err)
+		}
+		fmt.Println(string(jsonEffective))
+	case "text":
+		for key, resolved := range effective {
+			fmt.Printf("%s=%s (from %s)\n", key, resolved.Value, resolved.Scope)
+		}
+	default:
+		return fmt.Errorf("cannot resolve scope: unknown format %s", cmd.Format)
+	}
+	return nil
+}
+
+type SecretBackendConfig struct {
+	Backend      string `long:"backend" default:"aes" enum:"aes,age" description:"encryption backend to use"`
+	KeyFile      string `long:"keyfile" description:"path to the AES-256 key file (aes backend)"`
+	AgeRecipient string `long:"age-recipient" description:"age recipient to encrypt for (age backend)"`
+	AgeIdentity  string `long:"age-identity" description:"age identity to decrypt with (age backend)"`
+}
+
+func (cfg *SecretBackendConfig) Cipher() (configlite.Cipher, error) {
+	switch cfg.Backend {
+	case "aes":
+		return configlite.NewAESKeyfileCipher(cfg.KeyFile)
+	case "age":
+		return configlite.NewAgeCipher(cfg.AgeRecipient, cfg.AgeIdentity)
+	default:
+		return nil, fmt.Errorf("unknown secret backend: %s", cfg.Backend)
+	}
+}
+
+type SecretSetCmd struct {
+	CommonConfig        `embed:""`
+	SecretBackendConfig `embed:""`
+	KeyID               string `long:"key-id" required:"" description:"identifier recorded alongside the ciphertext"`
+	Application         string `arg:""`
+	Configuration       string `arg:""`
+	Value               string `arg:""`
+}
+
+func (cmd *SecretSetCmd) Run() error {
+	repo, err := configlite.New(cmd.Database)
+	if err != nil {
+		return fmt.Errorf("cannot open configuration database: %w", err)
+	}
+
+	cipher, err := cmd.Cipher()
+	if err != nil {
+		return fmt.Errorf("cannot build %s cipher: %w", cmd.Backend, err)
+	}
+
+	if err := repo.RegisterEncryptionKey(cmd.KeyID, cmd.Backend, cipher); err != nil {
+		return fmt.Errorf("cannot register encryption key %s: %w", cmd.KeyID, err)
+	}
+
+	return repo.UpsertSecret(cmd.Application, cmd.Configuration, cmd.Value)
+}
+
+type SecretGetCmd struct {
+	CommonConfig        `embed:""`
+	SecretBackendConfig `embed:""`
+	Reveal              bool   `long:"reveal" help:"decrypt and print the plaintext value instead of a redacted sentinel"`
+	Application         string `arg:""`
+	Configuration       string `arg:""`
+}
+
+func (cmd *SecretGetCmd) Run() error {
+	repo, err := configlite.New(cmd.Database)
+	if err != nil {
+		return fmt.Errorf("cannot open configuration database: %w", err)
+	}
+
+	var opts []configlite.ConfigOption
+	if cmd.Reveal {
+		cipher, err := cmd.Cipher()
+		if err != nil {
+			return fmt.Errorf("cannot build %s cipher: %w", cmd.Backend, err)
+		}
+		opts = append(opts, configlite.WithDecrypter(cipher))
+	}
+
+	value, err := repo.GetConfig(cmd.Application, cmd.Configuration, opts...)
+	if err != nil {
+		return fmt.Errorf("cannot get secret (%s, %s): %w", cmd.Application, cmd.Configuration, err)
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+type SecretCmd struct {
+	Set SecretSetCmd `cmd:""`
+	Get SecretGetCmd `cmd:""`
+}
+
+type ServeCmd struct {
+	CommonConfig `embed:""`
+	HTTPAddr     string        `long:"http-addr" default:":8080" description:"address to serve HTTP/JSON on"`
+	GRPCAddr     string        `long:"grpc-addr" default:":8081" description:"address to serve gRPC on"`
+	PollInterval time.Duration `long:"poll-interval" default:"1s" description:"how often to check for changes made by other processes"`
+}
+
+func (cmd *ServeCmd) Run() error {
+	repo, err := configlite.New(cmd.Database)
+	if err != nil {
+		return fmt.Errorf("cannot open configuration database: %w", err)
+	}
+
+	server := configliteserver.New(repo)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go server.Run(ctx, cmd.PollInterval)
+
+	grpcListener, err := net.Listen("tcp", cmd.GRPCAddr)
+	if err != nil {
+		return fmt.Errorf("cannot listen for gRPC on %s: %w", cmd.GRPCAddr, err)
+	}
+	go func() {
+		if err := server.GRPCServer().Serve(grpcListener); err != nil {
+			log.Println("gRPC server stopped:", err)
+		}
+	}()
+
+	httpServer := &http.Server{Addr: cmd.HTTPAddr, Handler: server.HTTPHandler()}
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	log.Printf("serving configlite over HTTP on %s and gRPC on %s", cmd.HTTPAddr, cmd.GRPCAddr)
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("HTTP server stopped: %w", err)
+	}
+	return nil
+}
+
 func main() {
 	var cli struct {
 		ListApp      ListAppCmd      `cmd:"" aliases:"la"`
 		ListConfigs  ListConfigsCmd  `cmd:"" aliases:"lc"`
 		UpsertConfig UpsertConfigCmd `cmd:"" aliases:"uc"`
 		DeleteConfig DeleteConfigCmd `cmd:"" aliases:"dc"`
+		Validate     ValidateCmd     `cmd:"" aliases:"v"`
+		Export       ExportCmd       `cmd:"" aliases:"ex"`
+		Import       ImportCmd       `cmd:"" aliases:"im"`
+		Secret       SecretCmd       `cmd:""`
+		Serve        ServeCmd        `cmd:""`
+		Resolve      ResolveCmd      `cmd:"" aliases:"r"`
 	}
 
 	ctx := kong.Parse(&cli, kong.Vars{"default_config_file": configlite.DefaultConfigurationFile()})
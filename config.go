@@ -1,10 +1,19 @@
 // Package configlite provides an abstraction of application configuration values
 // stored in a sqlite database. Several application can then share a single configuration database.
 //
+// Two lookup methods cover the exact-match and hierarchical cases
+// deliberately, rather than one method branching on a flag: GetConfig looks
+// up applicationName exactly, while ResolveConfig treats it as a
+// "/"-delimited scope and walks its ancestors. GetConfig stays exact-match
+// (not hierarchical) so existing callers - schema, secrets, the
+// interceptors, the server - keep their current behavior; see GetConfig's
+// doc comment for the full rationale.
+//
 //nolint:all
 package configlite
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -15,6 +24,12 @@ import (
 
 type Repository struct {
 	db *sql.DB
+
+	activeKeyID  string
+	activeCipher Cipher
+
+	readInterceptors  []ReadInterceptor
+	writeInterceptors []WriteInterceptor
 }
 
 var ErrConfigNotFound = fmt.Errorf("configuration value not found")
@@ -68,9 +83,38 @@ func (r *Repository) GetApps() ([]string, error) {
 	return apps, nil
 }
 
-func (r *Repository) GetConfigs(applicationName string) (map[string]string, error) {
+// AppsWithConfigs lists every application that has at least one
+// configuration value stored, by querying configurations directly rather
+// than the applications table: rawUpsertConfig writes configurations rows
+// without registering the application, so applications is frequently empty
+// even for a database full of real data. Export and configliteserver's
+// change poller use this instead of GetApps for that reason.
+func (r *Repository) AppsWithConfigs() ([]string, error) {
+	rows, err := r.db.Query(`SELECT DISTINCT application_name FROM configurations ORDER BY application_name`)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query applications with configurations: %w", err)
+	}
+	defer rows.Close()
+
+	apps := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("cannot scan application name: %w", err)
+		}
+		apps = append(apps, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot browse applications with configurations: %w", err)
+	}
+	return apps, nil
+}
+
+func (r *Repository) GetConfigs(applicationName string, opts ...ConfigOption) (map[string]string, error) {
+	options := applyConfigOptions(opts)
+
 	rows, err := r.db.Query(`
-		SELECT configuration_name, configuration_value
+		SELECT configuration_name, configuration_value, configuration_encrypted, key_id
 		FROM configurations
 		WHERE application_name = ?`, applicationName)
 	if err != nil {
@@ -78,24 +122,59 @@ func (r *Repository) GetConfigs(applicationName string) (map[string]string, erro
 			fmt.Errorf("cannot get configurations from database: %s - %w", applicationName, err)
 	}
 	defer rows.Close()
-	configs := map[string]string{}
+	raw := map[string]string{}
 	for rows.Next() {
 		var name, value string
-		if err := rows.Scan(&name, &value); err != nil {
+		var encrypted []byte
+		var keyID sql.NullString
+		if err := rows.Scan(&name, &value, &encrypted, &keyID); err != nil {
 			return nil, fmt.Errorf("cannot scan single config: %s - %w", applicationName, err)
 		}
-		configs[name] = value
+		resolved, err := resolveSecret(value, encrypted, keyID, options)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve config %s.%s: %w", applicationName, name, err)
+		}
+		raw[name] = resolved
 	}
 	if err := rows.Err(); err != nil {
 		return nil,
 			fmt.Errorf("cannot iterate over all configurations: %s - %w", applicationName, err)
 	}
+
+	read := chainRead(r.readInterceptors, func(ctx context.Context, applicationName, configName string) (string, error) {
+		return raw[configName], nil
+	})
+	configs := map[string]string{}
+	for name := range raw {
+		value, err := read(options.ctx, applicationName, name)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve config %s.%s: %w", applicationName, name, err)
+		}
+		configs[name] = value
+	}
 	return configs, nil
 }
 
-func (r *Repository) GetConfig(applicationName, configName string) (string, error) {
+// GetConfig looks up a single configuration value by its exact application
+// name, with no scope-hierarchy walking: applicationName must match a
+// configurations row exactly, or ErrConfigNotFound is returned. This is a
+// deliberate deviation from ResolveConfig, which treats applicationName as a
+// "/"-delimited scope and falls back to its ancestors; GetConfig stays
+// exact-match so the callers that already depend on it (schema, secrets,
+// interceptors, the server, the CLI's secret get) keep their existing
+// behavior. Use ResolveConfig when the caller wants hierarchy fallback.
+func (r *Repository) GetConfig(applicationName, configName string, opts ...ConfigOption) (string, error) {
+	options := applyConfigOptions(opts)
+
+	read := chainRead(r.readInterceptors, func(ctx context.Context, applicationName, configName string) (string, error) {
+		return r.rawGetConfig(applicationName, configName, options)
+	})
+	return read(options.ctx, applicationName, configName)
+}
+
+func (r *Repository) rawGetConfig(applicationName, configName string, options configOptions) (string, error) {
 	rows, err := r.db.Query(`
-		SELECT configuration_value
+		SELECT configuration_value, configuration_encrypted, key_id
 		FROM configurations
 		WHERE application_name = ?
 			AND configuration_name = ?`, applicationName, configName)
@@ -108,12 +187,18 @@ func (r *Repository) GetConfig(applicationName, configName string) (string, erro
 
 	for rows.Next() {
 		var value string
-		if err := rows.Scan(&value); err != nil {
+		var encrypted []byte
+		var keyID sql.NullString
+		if err := rows.Scan(&value, &encrypted, &keyID); err != nil {
 			return "",
 				fmt.Errorf("cannot scan configuration value from database: (%s, %s) - %w",
 					applicationName, configName, err)
 		}
-		return value, nil
+		resolved, err := resolveSecret(value, encrypted, keyID, options)
+		if err != nil {
+			return "", fmt.Errorf("cannot resolve config (%s, %s): %w", applicationName, configName, err)
+		}
+		return resolved, nil
 	}
 	if err := rows.Err(); err != nil {
 		return "",
@@ -135,7 +220,16 @@ func (r *Repository) RegisterApplication(applicationName string) error {
 	return err
 }
 
-func (r *Repository) UpsertConfig(applicationName, configName, configValue string) error {
+func (r *Repository) UpsertConfig(applicationName, configName, configValue string, opts ...ConfigOption) error {
+	options := applyConfigOptions(opts)
+
+	write := chainWrite(r.writeInterceptors, func(ctx context.Context, action WriteAction, applicationName, configName, configValue string) error {
+		return r.rawUpsertConfig(applicationName, configName, configValue)
+	})
+	return write(options.ctx, WriteActionUpsert, applicationName, configName, configValue)
+}
+
+func (r *Repository) rawUpsertConfig(applicationName, configName, configValue string) error {
 	_, err := r.db.Exec(`
 		INSERT INTO configurations (application_name, configuration_name, configuration_value)
 		VALUES (?1, ?2, ?3)
@@ -147,7 +241,16 @@ func (r *Repository) UpsertConfig(applicationName, configName, configValue strin
 	return err
 }
 
-func (r *Repository) DeleteConfig(applicationName, configName string, likePattern bool) error {
+func (r *Repository) DeleteConfig(applicationName, configName string, likePattern bool, opts ...ConfigOption) error {
+	options := applyConfigOptions(opts)
+
+	write := chainWrite(r.writeInterceptors, func(ctx context.Context, action WriteAction, applicationName, configName, configValue string) error {
+		return r.rawDeleteConfig(applicationName, configName, likePattern)
+	})
+	return write(options.ctx, WriteActionDelete, applicationName, configName, "")
+}
+
+func (r *Repository) rawDeleteConfig(applicationName, configName string, likePattern bool) error {
 	query := func() string {
 		if likePattern {
 			return `DELETE FROM configurations
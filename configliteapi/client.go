@@ -0,0 +1,91 @@
+package configliteapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ConfigServiceClient is the client side of the ConfigService gRPC service.
+type ConfigServiceClient interface {
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*ConfigValue, error)
+	GetConfigs(ctx context.Context, in *GetConfigsRequest, opts ...grpc.CallOption) (*ConfigMap, error)
+	UpsertConfig(ctx context.Context, in *UpsertConfigRequest, opts ...grpc.CallOption) (*Empty, error)
+	DeleteConfig(ctx context.Context, in *DeleteConfigRequest, opts ...grpc.CallOption) (*Empty, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ConfigService_WatchClient, error)
+}
+
+type configServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewConfigServiceClient builds a ConfigServiceClient over cc. cc must have
+// been dialed with grpc.WithDefaultCallOptions(grpc.ForceCodec(configliteapi.Codec)).
+func NewConfigServiceClient(cc grpc.ClientConnInterface) ConfigServiceClient {
+	return &configServiceClient{cc: cc}
+}
+
+func (c *configServiceClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*ConfigValue, error) {
+	out := new(ConfigValue)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/GetConfig", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) GetConfigs(ctx context.Context, in *GetConfigsRequest, opts ...grpc.CallOption) (*ConfigMap, error) {
+	out := new(ConfigMap)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/GetConfigs", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) UpsertConfig(ctx context.Context, in *UpsertConfigRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/UpsertConfig", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) DeleteConfig(ctx context.Context, in *DeleteConfigRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/DeleteConfig", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ConfigService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &serviceDesc.Streams[0], "/"+serviceName+"/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configServiceWatchClient{stream}
+	if err := x.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// ConfigService_WatchClient is the client side of the Watch server-stream.
+type ConfigService_WatchClient interface {
+	Recv() (*ChangeEvent, error)
+	grpc.ClientStream
+}
+
+type configServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *configServiceWatchClient) Recv() (*ChangeEvent, error) {
+	m := new(ChangeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,34 @@
+package configliteapi
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName is the gRPC content-subtype under which jsonCodec is
+// registered. Servers must be built with grpc.ForceServerCodec(Codec) and
+// clients must dial with grpc.WithDefaultCallOptions(grpc.ForceCodec(Codec))
+// so that every RPC on a ConfigService connection uses it.
+const CodecName = "json"
+
+// Codec is the encoding.Codec ConfigService messages are marshaled with.
+var Codec encoding.Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return CodecName
+}
+
+func init() {
+	encoding.RegisterCodec(Codec)
+}
@@ -0,0 +1,60 @@
+// Package configliteapi defines the wire messages and gRPC service
+// description shared by configliteserver and configliteclient. There is no
+// .proto file: messages are plain JSON-tagged structs carried over gRPC
+// through the "json" codec registered in this package, rather than through
+// generated protobuf marshaling.
+package configliteapi
+
+// GetConfigRequest is the request for the ConfigService.GetConfig RPC.
+type GetConfigRequest struct {
+	Application string `json:"application"`
+	Key         string `json:"key"`
+}
+
+// ConfigValue is the response for the ConfigService.GetConfig RPC.
+type ConfigValue struct {
+	Value string `json:"value"`
+}
+
+// GetConfigsRequest is the request for the ConfigService.GetConfigs RPC.
+type GetConfigsRequest struct {
+	Application string `json:"application"`
+}
+
+// ConfigMap is the response for the ConfigService.GetConfigs RPC.
+type ConfigMap struct {
+	Configs map[string]string `json:"configs"`
+}
+
+// UpsertConfigRequest is the request for the ConfigService.UpsertConfig RPC.
+type UpsertConfigRequest struct {
+	Application string `json:"application"`
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+}
+
+// DeleteConfigRequest is the request for the ConfigService.DeleteConfig RPC.
+type DeleteConfigRequest struct {
+	Application string `json:"application"`
+	Key         string `json:"key"`
+	LikePattern bool   `json:"likePattern"`
+}
+
+// Empty is the response for RPCs that return nothing but an error.
+type Empty struct{}
+
+// WatchRequest is the request for the ConfigService.Watch server-streaming
+// RPC. KeyPattern is matched against changed keys with path.Match; an empty
+// KeyPattern matches every key of Application.
+type WatchRequest struct {
+	Application string `json:"application"`
+	KeyPattern  string `json:"keyPattern"`
+}
+
+// ChangeEvent is one message of the ConfigService.Watch response stream.
+type ChangeEvent struct {
+	Application string `json:"application"`
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Deleted     bool   `json:"deleted"`
+}
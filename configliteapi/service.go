@@ -0,0 +1,129 @@
+package configliteapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// serviceName is the gRPC full service name, as it would appear in a
+// ConfigService.proto had one been compiled with protoc.
+const serviceName = "configliteapi.ConfigService"
+
+// ConfigServiceServer is implemented by configliteserver to back the
+// ConfigService gRPC service.
+type ConfigServiceServer interface {
+	GetConfig(context.Context, *GetConfigRequest) (*ConfigValue, error)
+	GetConfigs(context.Context, *GetConfigsRequest) (*ConfigMap, error)
+	UpsertConfig(context.Context, *UpsertConfigRequest) (*Empty, error)
+	DeleteConfig(context.Context, *DeleteConfigRequest) (*Empty, error)
+	Watch(*WatchRequest, ConfigService_WatchServer) error
+}
+
+// ConfigService_WatchServer is the server side of the Watch server-stream.
+type ConfigService_WatchServer interface {
+	Send(*ChangeEvent) error
+	grpc.ServerStream
+}
+
+type configServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (s *configServiceWatchServer) Send(e *ChangeEvent) error {
+	return s.ServerStream.SendMsg(e)
+}
+
+// RegisterConfigServiceServer registers srv with s so it serves the
+// ConfigService RPCs. s must have been created with
+// grpc.ForceServerCodec(configliteapi.Codec).
+func RegisterConfigServiceServer(s grpc.ServiceRegistrar, srv ConfigServiceServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+func getConfigHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: serviceName + "/GetConfig"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ConfigServiceServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func getConfigsHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(GetConfigsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).GetConfigs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: serviceName + "/GetConfigs"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ConfigServiceServer).GetConfigs(ctx, req.(*GetConfigsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func upsertConfigHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(UpsertConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).UpsertConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: serviceName + "/UpsertConfig"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ConfigServiceServer).UpsertConfig(ctx, req.(*UpsertConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func deleteConfigHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(DeleteConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigServiceServer).DeleteConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: serviceName + "/DeleteConfig"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ConfigServiceServer).DeleteConfig(ctx, req.(*DeleteConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func watchHandler(srv any, stream grpc.ServerStream) error {
+	in := new(WatchRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(ConfigServiceServer).Watch(in, &configServiceWatchServer{stream})
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*ConfigServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetConfig", Handler: getConfigHandler},
+		{MethodName: "GetConfigs", Handler: getConfigsHandler},
+		{MethodName: "UpsertConfig", Handler: upsertConfigHandler},
+		{MethodName: "DeleteConfig", Handler: deleteConfigHandler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       watchHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "configliteapi/service.go",
+}
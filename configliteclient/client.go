@@ -0,0 +1,117 @@
+// Package configliteclient is a Go client for a configlite.Repository
+// exposed remotely by configliteserver, so several applications can share
+// one configuration daemon over the network instead of each opening the
+// sqlite file directly.
+package configliteclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dgsb/configlite/configliteapi"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client is a configliteserver client. Its method set mirrors
+// configlite.Repository so callers can switch between a local database and
+// a remote daemon with minimal changes.
+type Client struct {
+	conn *grpc.ClientConn
+	api  configliteapi.ConfigServiceClient
+}
+
+// Dial connects to a configliteserver gRPC endpoint at target. configliteserver
+// never sets up TLS, so Dial defaults to insecure transport credentials;
+// pass grpc.WithTransportCredentials with opts to use TLS instead.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(configliteapi.Codec)),
+	}, opts...)
+
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot dial configliteserver at %s: %w", target, err)
+	}
+
+	return &Client{conn: conn, api: configliteapi.NewConfigServiceClient(conn)}, nil
+}
+
+// Close releases the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// GetConfig fetches a single configuration value.
+func (c *Client) GetConfig(ctx context.Context, app, key string) (string, error) {
+	resp, err := c.api.GetConfig(ctx, &configliteapi.GetConfigRequest{Application: app, Key: key})
+	if err != nil {
+		return "", fmt.Errorf("cannot get config (%s, %s): %w", app, key, err)
+	}
+	return resp.Value, nil
+}
+
+// GetConfigs fetches every configuration value registered for app.
+func (c *Client) GetConfigs(ctx context.Context, app string) (map[string]string, error) {
+	resp, err := c.api.GetConfigs(ctx, &configliteapi.GetConfigsRequest{Application: app})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get configs for %s: %w", app, err)
+	}
+	return resp.Configs, nil
+}
+
+// UpsertConfig creates or updates a single configuration value.
+func (c *Client) UpsertConfig(ctx context.Context, app, key, value string) error {
+	_, err := c.api.UpsertConfig(ctx, &configliteapi.UpsertConfigRequest{
+		Application: app,
+		Key:         key,
+		Value:       value,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot upsert config (%s, %s): %w", app, key, err)
+	}
+	return nil
+}
+
+// DeleteConfig deletes a single configuration value, or every value whose
+// name matches key as an SQL LIKE pattern when likePattern is true.
+func (c *Client) DeleteConfig(ctx context.Context, app, key string, likePattern bool) error {
+	_, err := c.api.DeleteConfig(ctx, &configliteapi.DeleteConfigRequest{
+		Application: app,
+		Key:         key,
+		LikePattern: likePattern,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete config (%s, %s): %w", app, key, err)
+	}
+	return nil
+}
+
+// Watch streams changes to app's configuration whose key matches keyPattern
+// (a path.Match-style glob; empty matches every key) until ctx is canceled.
+func (c *Client) Watch(ctx context.Context, app, keyPattern string) (<-chan configliteapi.ChangeEvent, error) {
+	stream, err := c.api.Watch(ctx, &configliteapi.WatchRequest{Application: app, KeyPattern: keyPattern})
+	if err != nil {
+		return nil, fmt.Errorf("cannot watch %s: %w", app, err)
+	}
+
+	events := make(chan configliteapi.ChangeEvent)
+	go func() {
+		defer close(events)
+		for {
+			e, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case events <- *e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
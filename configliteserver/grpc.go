@@ -0,0 +1,95 @@
+package configliteserver
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/dgsb/configlite/configliteapi"
+
+	"google.golang.org/grpc"
+)
+
+// GRPCServer builds a *grpc.Server with the ConfigService registered on it,
+// using configliteapi.Codec instead of protobuf wire encoding.
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.ForceServerCodec(configliteapi.Codec)}, opts...)
+	grpcServer := grpc.NewServer(opts...)
+	configliteapi.RegisterConfigServiceServer(grpcServer, (*grpcService)(s))
+	return grpcServer
+}
+
+// grpcService implements configliteapi.ConfigServiceServer over a Server,
+// without exposing that implementation as part of Server's own API.
+type grpcService Server
+
+func (s *grpcService) GetConfig(ctx context.Context, req *configliteapi.GetConfigRequest) (*configliteapi.ConfigValue, error) {
+	value, err := s.repo.GetConfig(req.Application, req.Key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get config (%s, %s): %w", req.Application, req.Key, err)
+	}
+	return &configliteapi.ConfigValue{Value: value}, nil
+}
+
+func (s *grpcService) GetConfigs(ctx context.Context, req *configliteapi.GetConfigsRequest) (*configliteapi.ConfigMap, error) {
+	configs, err := s.repo.GetConfigs(req.Application)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get configs for %s: %w", req.Application, err)
+	}
+	return &configliteapi.ConfigMap{Configs: configs}, nil
+}
+
+// UpsertConfig does not publish to the Hub directly: the write's own
+// config_changes trigger row is picked up by pollExternalChanges like any
+// other writer's, so each change is only ever announced once, at the cost of
+// up to one poll interval of latency.
+func (s *grpcService) UpsertConfig(ctx context.Context, req *configliteapi.UpsertConfigRequest) (*configliteapi.Empty, error) {
+	if err := s.repo.UpsertConfig(req.Application, req.Key, req.Value); err != nil {
+		return nil, fmt.Errorf("cannot upsert config (%s, %s): %w", req.Application, req.Key, err)
+	}
+	return &configliteapi.Empty{}, nil
+}
+
+// DeleteConfig does not publish to the Hub directly; see UpsertConfig.
+func (s *grpcService) DeleteConfig(ctx context.Context, req *configliteapi.DeleteConfigRequest) (*configliteapi.Empty, error) {
+	if err := s.repo.DeleteConfig(req.Application, req.Key, req.LikePattern); err != nil {
+		return nil, fmt.Errorf("cannot delete config (%s, %s): %w", req.Application, req.Key, err)
+	}
+	return &configliteapi.Empty{}, nil
+}
+
+func (s *grpcService) Watch(req *configliteapi.WatchRequest, stream configliteapi.ConfigService_WatchServer) error {
+	ch, cancel := s.hub.Subscribe()
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case e, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if e.Application != req.Application {
+				continue
+			}
+			if req.KeyPattern != "" {
+				matched, err := path.Match(req.KeyPattern, e.Key)
+				if err != nil {
+					return fmt.Errorf("invalid key pattern %q: %w", req.KeyPattern, err)
+				}
+				if !matched {
+					continue
+				}
+			}
+			if err := stream.Send(&configliteapi.ChangeEvent{
+				Application: e.Application,
+				Key:         e.Key,
+				Value:       e.Value,
+				Deleted:     e.Deleted,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package configliteserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/dgsb/configlite"
+)
+
+// HTTPHandler returns an http.Handler exposing GetConfig, GetConfigs,
+// UpsertConfig, DeleteConfig and a streaming Watch under /v1/apps/:
+//
+//	GET    /v1/apps/{app}/configs            -> GetConfigs
+//	GET    /v1/apps/{app}/configs/{key}      -> GetConfig
+//	PUT    /v1/apps/{app}/configs/{key}      -> UpsertConfig (body: {"value": "..."})
+//	DELETE /v1/apps/{app}/configs/{key}      -> DeleteConfig (?like=true for a LIKE pattern)
+//	GET    /v1/apps/{app}/watch?pattern=...  -> newline-delimited JSON stream of Event values
+func (s *Server) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/apps/", s.handleApps)
+	return mux
+}
+
+func (s *Server) handleApps(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/v1/apps/")
+	segments := strings.SplitN(strings.Trim(rest, "/"), "/", 3)
+	if len(segments) < 2 || segments[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	app := segments[0]
+
+	switch segments[1] {
+	case "configs":
+		switch len(segments) {
+		case 2:
+			s.handleGetConfigs(w, r, app)
+		case 3:
+			s.handleConfig(w, r, app, segments[2])
+		default:
+			http.NotFound(w, r)
+		}
+	case "watch":
+		s.handleWatch(w, r, app)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleGetConfigs(w http.ResponseWriter, r *http.Request, app string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	configs, err := s.repo.GetConfigs(app)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, configs)
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request, app, key string) {
+	switch r.Method {
+	case http.MethodGet:
+		value, err := s.repo.GetConfig(app, key)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, configliteValue{Value: value})
+
+	case http.MethodPut:
+		// Not published to the Hub directly: the write's own config_changes
+		// trigger row is picked up by pollExternalChanges like any other
+		// writer's, so each change is only ever announced once, at the cost
+		// of up to one poll interval of latency.
+		var body configliteValue
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("cannot decode request body: %s", err), http.StatusBadRequest)
+			return
+		}
+		if err := s.repo.UpsertConfig(app, key, body.Value); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		// See the PUT case above: no direct Hub.Publish here either.
+		likePattern := r.URL.Query().Get("like") == "true"
+		if err := s.repo.DeleteConfig(app, key, likePattern); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request, app string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	pattern := r.URL.Query().Get("pattern")
+
+	ch, cancel := s.hub.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	enc := json.NewEncoder(w)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			if e.Application != app {
+				continue
+			}
+			if pattern != "" {
+				matched, err := path.Match(pattern, e.Key)
+				if err != nil || !matched {
+					continue
+				}
+			}
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+type configliteValue struct {
+	Value string `json:"value"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, configlite.ErrConfigNotFound) {
+		status = http.StatusNotFound
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,123 @@
+package configliteserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dgsb/configlite"
+)
+
+// Event is a single configuration change, discovered by polling
+// config_changes. Every write, whether made through this Server or by
+// another process sharing the same database file, is only ever announced
+// this way, so a change is never published twice.
+type Event struct {
+	Application string
+	Key         string
+	Value       string
+	Deleted     bool
+}
+
+// Hub fans out Events to every active subscriber, most commonly one per
+// open Watch RPC or HTTP watch connection.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// NewHub builds an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: map[chan Event]struct{}{}}
+}
+
+// Subscribe registers a new listener. The caller must call cancel once it
+// stops reading from ch, to release the subscription.
+func (h *Hub) Subscribe() (ch chan Event, cancel func()) {
+	ch = make(chan Event, 16)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// Publish fans e out to every current subscriber. Slow subscribers that
+// would block are skipped rather than stalling the publisher.
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// pollExternalChanges periodically diffs config_changes against the last
+// version seen for each application, publishing an Event for every change,
+// whether made through this Server or by another writer (e.g. another
+// process, or the CLI, sharing the same database file). An application is
+// polled once it has at least one stored value at a tick, and from then on
+// even after its last value is deleted; changes to an application whose
+// values are all created and deleted between two ticks are not announced.
+func (h *Hub) pollExternalChanges(ctx context.Context, repo *configlite.Repository, interval time.Duration) {
+	lastVersion := map[string]int64{}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// AppsWithConfigs, not GetApps: UpsertConfig never registers the
+			// application, so polling the applications table would miss
+			// changes for any application that was never explicitly
+			// registered.
+			apps, err := repo.AppsWithConfigs()
+			if err != nil {
+				continue
+			}
+			// Keep polling applications seen earlier, so the deletion of an
+			// application's last value is still announced.
+			listed := map[string]bool{}
+			for _, app := range apps {
+				listed[app] = true
+			}
+			for app := range lastVersion {
+				if !listed[app] {
+					apps = append(apps, app)
+				}
+			}
+			for _, app := range apps {
+				changes, maxVersion, err := repo.PollChanges(app, lastVersion[app])
+				if err != nil {
+					continue
+				}
+				for _, change := range changes {
+					value, err := repo.GetConfig(change.Application, change.Key)
+					h.Publish(Event{
+						Application: change.Application,
+						Key:         change.Key,
+						Value:       value,
+						Deleted:     err != nil,
+					})
+				}
+				lastVersion[app] = maxVersion
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package configliteserver exposes a configlite.Repository over HTTP/JSON
+// and gRPC, with change-notification streaming, so several applications can
+// share one configuration daemon over the network instead of each opening
+// the sqlite file directly.
+package configliteserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgsb/configlite"
+)
+
+// DefaultPollInterval is how often Server checks config_changes for writes
+// made by another process sharing the same database file.
+const DefaultPollInterval = time.Second
+
+// Server backs both the gRPC ConfigService and the HTTP handler with a
+// single configlite.Repository and change-notification Hub.
+type Server struct {
+	repo *configlite.Repository
+	hub  *Hub
+}
+
+// New builds a Server over repo. Call Run to start polling for changes
+// before serving GRPCServer or HTTPHandler traffic.
+func New(repo *configlite.Repository) *Server {
+	return &Server{repo: repo, hub: NewHub()}
+}
+
+// Run polls repo for changes at pollInterval until ctx is canceled. It must
+// be running for Watch subscribers to see any write, including writes made
+// through this Server's own HTTP and gRPC handlers.
+func (s *Server) Run(ctx context.Context, pollInterval time.Duration) {
+	s.hub.pollExternalChanges(ctx, s.repo, pollInterval)
+}
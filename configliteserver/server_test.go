@@ -0,0 +1,99 @@
+package configliteserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgsb/configlite"
+)
+
+func TestWatchDoesNotDoublePublishLocalWrites(t *testing.T) {
+	dbFile, err := os.CreateTemp("", "configlite-*.db")
+	if err != nil {
+		t.Fatalf("cannot create temp database file: %s", err)
+	}
+	dbFile.Close()
+	defer os.Remove(dbFile.Name())
+
+	repo, err := configlite.New(dbFile.Name())
+	if err != nil {
+		t.Fatalf("cannot open configuration database: %s", err)
+	}
+	defer repo.Close()
+
+	// Deliberately not calling RegisterApplication: UpsertConfig never
+	// registers the application on its own (see AppsWithConfigs), and
+	// pollExternalChanges must still discover and deliver this write.
+	server := New(repo)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Run(ctx, 10*time.Millisecond)
+
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	watchReq, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/apps/app1/watch", nil)
+	if err != nil {
+		t.Fatalf("cannot build watch request: %s", err)
+	}
+	watchResp, err := http.DefaultClient.Do(watchReq)
+	if err != nil {
+		t.Fatalf("cannot start watch: %s", err)
+	}
+	defer watchResp.Body.Close()
+
+	events := make(chan map[string]any, 16)
+	go func() {
+		scanner := bufio.NewScanner(watchResp.Body)
+		for scanner.Scan() {
+			var e map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				return
+			}
+			events <- e
+		}
+	}()
+
+	body, err := json.Marshal(map[string]string{"value": "bar"})
+	if err != nil {
+		t.Fatalf("cannot marshal put body: %s", err)
+	}
+	putReq, err := http.NewRequest(http.MethodPut, ts.URL+"/v1/apps/app1/configs/foo",
+		bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("cannot build put request: %s", err)
+	}
+	putResp, err := http.DefaultClient.Do(putReq)
+	if err != nil {
+		t.Fatalf("cannot put config: %s", err)
+	}
+	putResp.Body.Close()
+
+	var got []map[string]any
+	timeout := time.After(500 * time.Millisecond)
+	quiet := time.NewTimer(200 * time.Millisecond)
+	defer quiet.Stop()
+collect:
+	for {
+		select {
+		case e := <-events:
+			got = append(got, e)
+			quiet.Reset(200 * time.Millisecond)
+		case <-quiet.C:
+			break collect
+		case <-timeout:
+			break collect
+		}
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("expected exactly one watch event for one write, got %d: %v", len(got), got)
+	}
+}
@@ -0,0 +1,152 @@
+package configlite
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// PluginRequest is one line of the line-delimited JSON protocol spoken
+// between ExecPlugin and the plugin subprocess: one request per line on the
+// plugin's stdin, one response per line on its stdout.
+type PluginRequest struct {
+	Kind            string      `json:"kind"` // "read" or "write"
+	Action          WriteAction `json:"action,omitempty"`
+	ApplicationName string      `json:"application_name"`
+	ConfigName      string      `json:"config_name"`
+	ConfigValue     string      `json:"config_value,omitempty"`
+}
+
+// PluginResponse is the plugin's reply to a PluginRequest. Value carries the
+// (possibly rewritten) configuration value; a non-empty Error fails the call
+// with that message. A write is then rejected without calling next; a read
+// has already called next and returns the error instead of its value.
+type PluginResponse struct {
+	Value string `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ExecPlugin runs an out-of-process plugin as a subprocess and speaks the
+// line-delimited JSON protocol above over its stdin/stdout. One ExecPlugin
+// can supply a ReadInterceptor, a WriteInterceptor, or both, all funnelled
+// through the same subprocess call-by-call.
+type ExecPlugin struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+
+	mu sync.Mutex
+}
+
+// StartExecPlugin launches path as a subprocess plugin.
+func StartExecPlugin(path string, args ...string) (*ExecPlugin, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("cannot open plugin %s stdin: %w", path, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("cannot open plugin %s stdout: %w", path, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("cannot start plugin %s: %w", path, err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPluginResponseBytes)
+
+	return &ExecPlugin{cmd: cmd, stdin: stdin, stdout: scanner}, nil
+}
+
+// maxPluginResponseBytes bounds a single plugin response line so a large
+// configuration value (e.g. an embedded certificate bundle) doesn't get
+// rejected by bufio.Scanner's default 64KB token limit.
+const maxPluginResponseBytes = 8 * 1024 * 1024
+
+// Close closes the plugin's stdin and waits for it to exit.
+func (p *ExecPlugin) Close() error {
+	if err := p.stdin.Close(); err != nil {
+		return fmt.Errorf("cannot close plugin stdin: %w", err)
+	}
+	if err := p.cmd.Wait(); err != nil {
+		return fmt.Errorf("plugin exited with an error: %w", err)
+	}
+	return nil
+}
+
+func (p *ExecPlugin) call(req PluginRequest) (PluginResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return PluginResponse{}, fmt.Errorf("cannot marshal plugin request: %w", err)
+	}
+	if _, err := p.stdin.Write(append(data, '\n')); err != nil {
+		return PluginResponse{}, fmt.Errorf("cannot write plugin request: %w", err)
+	}
+
+	if !p.stdout.Scan() {
+		if err := p.stdout.Err(); err != nil {
+			return PluginResponse{}, fmt.Errorf("cannot read plugin response: %w", err)
+		}
+		return PluginResponse{}, fmt.Errorf("plugin closed its output unexpectedly")
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(p.stdout.Bytes(), &resp); err != nil {
+		return PluginResponse{}, fmt.Errorf("cannot decode plugin response: %w", err)
+	}
+	if resp.Error != "" {
+		return resp, fmt.Errorf("plugin rejected the request: %s", resp.Error)
+	}
+	return resp, nil
+}
+
+// ReadInterceptor returns a ReadInterceptor that forwards every read to the
+// plugin subprocess after the rest of the chain has resolved a value.
+func (p *ExecPlugin) ReadInterceptor() ReadInterceptor {
+	return func(ctx context.Context, applicationName, configName string, next ReadNext) (string, error) {
+		value, err := next(ctx, applicationName, configName)
+		if err != nil {
+			return "", err
+		}
+		resp, err := p.call(PluginRequest{
+			Kind:            "read",
+			ApplicationName: applicationName,
+			ConfigName:      configName,
+			ConfigValue:     value,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Value, nil
+	}
+}
+
+// WriteInterceptor returns a WriteInterceptor that forwards every write to
+// the plugin subprocess, passing through whatever value it returns to the
+// rest of the chain.
+func (p *ExecPlugin) WriteInterceptor() WriteInterceptor {
+	return func(ctx context.Context, action WriteAction, applicationName, configName, configValue string, next WriteNext) error {
+		resp, err := p.call(PluginRequest{
+			Kind:            "write",
+			Action:          action,
+			ApplicationName: applicationName,
+			ConfigName:      configName,
+			ConfigValue:     configValue,
+		})
+		if err != nil {
+			return err
+		}
+		return next(ctx, action, applicationName, configName, resp.Value)
+	}
+}
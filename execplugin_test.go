@@ -0,0 +1,109 @@
+package configlite
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestHelperProcess is not a real test: it's re-exec'd as the plugin
+// subprocess by the tests below, following the standard os/exec
+// self-re-exec pattern (see https://pkg.go.dev/os/exec#Cmd.Args examples).
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("CONFIGLITE_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req PluginRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			fmt.Fprintf(os.Stderr, "helper: cannot decode request: %s\n", err)
+			os.Exit(1)
+		}
+
+		var resp PluginResponse
+		switch {
+		case req.ConfigValue == "reject-me":
+			resp = PluginResponse{Error: "value rejected by helper plugin"}
+		default:
+			resp = PluginResponse{Value: strings.ToUpper(req.ConfigValue)}
+		}
+
+		b, err := json.Marshal(resp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "helper: cannot encode response: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stdout, string(b))
+	}
+}
+
+func startHelperPlugin(t *testing.T) *ExecPlugin {
+	t.Helper()
+
+	// The helper process inherits this from the environment at Start time,
+	// since StartExecPlugin leaves cmd.Env nil.
+	t.Setenv("CONFIGLITE_WANT_HELPER_PROCESS", "1")
+
+	plugin, err := StartExecPlugin(os.Args[0], "-test.run=TestHelperProcess")
+	if err != nil {
+		t.Fatalf("cannot start helper plugin: %s", err)
+	}
+	t.Cleanup(func() { plugin.Close() })
+	return plugin
+}
+
+func TestExecPluginReadInterceptorTransformsValue(t *testing.T) {
+	plugin := startHelperPlugin(t)
+
+	terminal := func(ctx context.Context, applicationName, configName string) (string, error) {
+		return "hello", nil
+	}
+	read := plugin.ReadInterceptor()
+
+	value, err := read(context.Background(), "app1", "key1", terminal)
+	if err != nil {
+		t.Fatalf("cannot run read interceptor: %s", err)
+	}
+	if value != "HELLO" {
+		t.Fatalf("expected %q, got %q", "HELLO", value)
+	}
+}
+
+func TestExecPluginWriteInterceptorPropagatesRejection(t *testing.T) {
+	plugin := startHelperPlugin(t)
+
+	write := plugin.WriteInterceptor()
+	err := write(context.Background(), WriteActionUpsert, "app1", "key1", "reject-me",
+		func(ctx context.Context, action WriteAction, applicationName, configName, configValue string) error {
+			t.Fatalf("next must not be called when the plugin rejects the write")
+			return nil
+		})
+	if err == nil {
+		t.Fatalf("expected the plugin's rejection to propagate as an error")
+	}
+}
+
+func TestExecPluginWriteInterceptorForwardsTransformedValue(t *testing.T) {
+	plugin := startHelperPlugin(t)
+
+	var got string
+	write := plugin.WriteInterceptor()
+	err := write(context.Background(), WriteActionUpsert, "app1", "key1", "hello",
+		func(ctx context.Context, action WriteAction, applicationName, configName, configValue string) error {
+			got = configValue
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("cannot run write interceptor: %s", err)
+	}
+	if got != "HELLO" {
+		t.Fatalf("expected next to receive %q, got %q", "HELLO", got)
+	}
+}
@@ -0,0 +1,205 @@
+package configlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// exportFormatVersion identifies the shape of the document produced by
+// Export and consumed by Import, independent of any per-application Schema
+// version declared via RegisterSchema.
+const exportFormatVersion = 1
+
+type exportDocument struct {
+	SchemaVersion int         `json:"schemaVersion" yaml:"schemaVersion"`
+	Apps          []exportApp `json:"apps" yaml:"apps"`
+}
+
+type exportApp struct {
+	Name    string            `json:"name" yaml:"name"`
+	Configs map[string]string `json:"configs" yaml:"configs"`
+}
+
+// ImportMode controls how Import reconciles a document against the
+// configuration values already stored for an application.
+type ImportMode int
+
+const (
+	// Merge upserts every key found in the document, leaving any other
+	// key already stored for the application untouched.
+	Merge ImportMode = iota
+	// Replace wipes every configuration value stored for an application
+	// before inserting the ones found in the document.
+	Replace
+	// DryRun computes and reports what Merge would change without writing
+	// anything to the database; keys missing from the document are never
+	// reported as removed.
+	DryRun
+)
+
+// ConfigDiff reports how Import would change, or changed, a single
+// application's configuration values.
+type ConfigDiff struct {
+	Added   map[string]string    `json:"added,omitempty" yaml:"added,omitempty"`
+	Changed map[string][2]string `json:"changed,omitempty" yaml:"changed,omitempty"`
+	Removed map[string]string    `json:"removed,omitempty" yaml:"removed,omitempty"`
+}
+
+// ImportReport maps an application name to the diff Import computed for it.
+type ImportReport map[string]ConfigDiff
+
+// Export writes every configuration value of the given applications (or, if
+// apps is empty, of every application with at least one stored value, as
+// listed by AppsWithConfigs) to w in the requested format ("json" or
+// "yaml"). The document can be checked into git, diffed, and later fed back
+// to Import to restore or seed a database. Encrypted values are exported as
+// RedactedSecret, not as plaintext or ciphertext, so Import cannot restore
+// them.
+func (r *Repository) Export(w io.Writer, format string, apps ...string) error {
+	if len(apps) == 0 {
+		all, err := r.AppsWithConfigs()
+		if err != nil {
+			return fmt.Errorf("cannot list applications to export: %w", err)
+		}
+		apps = all
+	}
+
+	doc := exportDocument{SchemaVersion: exportFormatVersion}
+	for _, app := range apps {
+		configs, err := r.GetConfigs(app)
+		if err != nil {
+			return fmt.Errorf("cannot export configuration for %s: %w", app, err)
+		}
+		doc.Apps = append(doc.Apps, exportApp{Name: app, Configs: configs})
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("cannot json encode export document: %w", err)
+		}
+		return nil
+	case "yaml":
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("cannot yaml encode export document: %w", err)
+		}
+		if _, err := w.Write(b); err != nil {
+			return fmt.Errorf("cannot write yaml export document: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+// Import reads a document produced by Export from r and applies it
+// according to mode, returning a per-application ConfigDiff describing what
+// was (DryRun: would be) changed.
+func (r *Repository) Import(rd io.Reader, format string, mode ImportMode) (ImportReport, error) {
+	var doc exportDocument
+	switch format {
+	case "json":
+		if err := json.NewDecoder(rd).Decode(&doc); err != nil {
+			return nil, fmt.Errorf("cannot json decode import document: %w", err)
+		}
+	case "yaml":
+		b, err := io.ReadAll(rd)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read yaml import document: %w", err)
+		}
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("cannot yaml decode import document: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown import format: %s", format)
+	}
+
+	report := ImportReport{}
+	for _, app := range doc.Apps {
+		existing, err := r.GetConfigs(app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read existing configuration for %s: %w", app.Name, err)
+		}
+		report[app.Name] = diffConfigs(existing, app.Configs, mode == Replace)
+
+		if mode == DryRun {
+			continue
+		}
+		if err := r.applyImport(app, mode); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func (r *Repository) applyImport(app exportApp, mode ImportMode) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("cannot start transaction to import %s: %w", app.Name, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`INSERT INTO applications (name) VALUES (?) ON CONFLICT DO NOTHING`, app.Name,
+	); err != nil {
+		return fmt.Errorf("cannot register application %s: %w", app.Name, err)
+	}
+
+	if mode == Replace {
+		if _, err := tx.Exec(
+			`DELETE FROM configurations WHERE application_name = ?`, app.Name,
+		); err != nil {
+			return fmt.Errorf("cannot wipe existing configuration for %s: %w", app.Name, err)
+		}
+	}
+
+	for k, v := range app.Configs {
+		if _, err := tx.Exec(`
+			INSERT INTO configurations (application_name, configuration_name, configuration_value)
+			VALUES (?1, ?2, ?3)
+			ON CONFLICT (application_name, configuration_name) DO
+			UPDATE SET configuration_value = ?3`, app.Name, k, v); err != nil {
+			return fmt.Errorf("cannot import %s.%s: %w", app.Name, k, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit import of %s: %w", app.Name, err)
+	}
+	return nil
+}
+
+func diffConfigs(old, new map[string]string, removeMissing bool) ConfigDiff {
+	diff := ConfigDiff{
+		Added:   map[string]string{},
+		Changed: map[string][2]string{},
+		Removed: map[string]string{},
+	}
+
+	for k, v := range new {
+		if ov, ok := old[k]; ok {
+			if ov != v {
+				diff.Changed[k] = [2]string{ov, v}
+			}
+		} else {
+			diff.Added[k] = v
+		}
+	}
+
+	if removeMissing {
+		for k, v := range old {
+			if _, ok := new[k]; !ok {
+				diff.Removed[k] = v
+			}
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,33 @@
+package configlite
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExportDefaultsToAppsWithConfigs(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	// UpsertConfig never registers the application in the applications
+	// table, so Export must not rely on GetApps to find it.
+	if err := repo.UpsertConfig("app1", "foo", "bar"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := repo.Export(&buf, "json"); err != nil {
+		t.Fatalf("cannot export: %s", err)
+	}
+
+	if !strings.Contains(buf.String(), `"app1"`) {
+		t.Fatalf("expected export to include app1, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"bar"`) {
+		t.Fatalf("expected export to include the configured value, got: %s", buf.String())
+	}
+}
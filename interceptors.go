@@ -0,0 +1,83 @@
+package configlite
+
+import "context"
+
+// ReadNext invokes the next step of a read interceptor chain, ultimately
+// reaching the database lookup itself.
+type ReadNext func(ctx context.Context, applicationName, configName string) (string, error)
+
+// ReadInterceptor wraps a GetConfig/GetConfigs lookup. It may inspect or
+// rewrite the value returned by next, short-circuit without calling next, or
+// reject the read by returning an error.
+type ReadInterceptor func(ctx context.Context, applicationName, configName string, next ReadNext) (string, error)
+
+// WriteAction distinguishes the two operations a WriteInterceptor can see.
+type WriteAction string
+
+const (
+	WriteActionUpsert WriteAction = "upsert"
+	WriteActionDelete WriteAction = "delete"
+)
+
+// WriteNext invokes the next step of a write interceptor chain, ultimately
+// reaching the database write itself. configValue is ignored for
+// WriteActionDelete.
+type WriteNext func(ctx context.Context, action WriteAction, applicationName, configName, configValue string) error
+
+// WriteInterceptor wraps an UpsertConfig/DeleteConfig call. It may validate
+// or rewrite the write before calling next, observe its outcome, or reject it
+// by returning an error instead of calling next.
+type WriteInterceptor func(ctx context.Context, action WriteAction, applicationName, configName, configValue string, next WriteNext) error
+
+// UseReadInterceptor appends i to the chain run around every subsequent
+// GetConfig and GetConfigs call. Interceptors run in registration order, each
+// wrapping the ones registered after it.
+func (r *Repository) UseReadInterceptor(i ReadInterceptor) {
+	r.readInterceptors = append(r.readInterceptors, i)
+}
+
+// UseWriteInterceptor appends i to the chain run around every subsequent
+// UpsertConfig and DeleteConfig call. Interceptors run in registration order,
+// each wrapping the ones registered after it.
+func (r *Repository) UseWriteInterceptor(i WriteInterceptor) {
+	r.writeInterceptors = append(r.writeInterceptors, i)
+}
+
+func chainRead(interceptors []ReadInterceptor, terminal ReadNext) ReadNext {
+	next := terminal
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		prevNext := next
+		next = func(ctx context.Context, applicationName, configName string) (string, error) {
+			return interceptor(ctx, applicationName, configName, prevNext)
+		}
+	}
+	return next
+}
+
+func chainWrite(interceptors []WriteInterceptor, terminal WriteNext) WriteNext {
+	next := terminal
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		prevNext := next
+		next = func(ctx context.Context, action WriteAction, applicationName, configName, configValue string) error {
+			return interceptor(ctx, action, applicationName, configName, configValue, prevNext)
+		}
+	}
+	return next
+}
+
+type callerContextKey struct{}
+
+// WithCaller attaches caller to ctx so write interceptors such as
+// AuditLogInterceptor can record who made a change.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// CallerFromContext returns the caller attached to ctx via WithCaller, if
+// any.
+func CallerFromContext(ctx context.Context) (string, bool) {
+	caller, ok := ctx.Value(callerContextKey{}).(string)
+	return caller, ok
+}
@@ -0,0 +1,107 @@
+package configlite
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var envVarPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// EnvExpansionInterceptor expands ${VAR} references in a configuration value
+// against the process environment, and ${app.key} references against repo
+// itself. References that cannot be resolved are left untouched.
+//
+// Because cross-references are resolved through repo.GetConfig, a value that
+// references itself, directly or through a cycle, will recurse until the
+// referenced key bottoms out or the call stack is exhausted; callers are
+// expected not to configure such cycles. Cross-referenced values are
+// resolved without a Decrypter, so a reference to an encrypted value expands
+// to RedactedSecret rather than the plaintext.
+func EnvExpansionInterceptor(repo *Repository) ReadInterceptor {
+	return func(ctx context.Context, applicationName, configName string, next ReadNext) (string, error) {
+		value, err := next(ctx, applicationName, configName)
+		if err != nil {
+			return "", err
+		}
+
+		expanded := envVarPattern.ReplaceAllStringFunc(value, func(match string) string {
+			ref := match[2 : len(match)-1]
+			if refApp, refKey, ok := strings.Cut(ref, "."); ok {
+				if v, err := repo.GetConfig(refApp, refKey, WithContext(ctx)); err == nil {
+					return v
+				}
+				return match
+			}
+			if v, ok := os.LookupEnv(ref); ok {
+				return v
+			}
+			return match
+		})
+		return expanded, nil
+	}
+}
+
+// AuditLogInterceptor records every successful UpsertConfig and DeleteConfig
+// call into the audit_log table, including the caller attached to ctx via
+// WithCaller, if any.
+func AuditLogInterceptor(repo *Repository) WriteInterceptor {
+	return func(ctx context.Context, action WriteAction, applicationName, configName, configValue string, next WriteNext) error {
+		if err := next(ctx, action, applicationName, configName, configValue); err != nil {
+			return err
+		}
+
+		caller, _ := CallerFromContext(ctx)
+		if _, err := repo.db.Exec(`
+			INSERT INTO audit_log (caller, application_name, configuration_name, action)
+			VALUES (?, ?, ?, ?)`,
+			caller, applicationName, configName, string(action)); err != nil {
+			return fmt.Errorf("cannot record audit log entry (%s, %s): %w", applicationName, configName, err)
+		}
+		return nil
+	}
+}
+
+// SizeLimitInterceptor rejects UpsertConfig calls whose value is larger than
+// maxBytes.
+func SizeLimitInterceptor(maxBytes int) WriteInterceptor {
+	return func(ctx context.Context, action WriteAction, applicationName, configName, configValue string, next WriteNext) error {
+		if action == WriteActionUpsert && len(configValue) > maxBytes {
+			return fmt.Errorf("value for (%s, %s) is %d bytes, which exceeds the limit of %d",
+				applicationName, configName, len(configValue), maxBytes)
+		}
+		return next(ctx, action, applicationName, configName, configValue)
+	}
+}
+
+// RateLimitInterceptor rejects a write once limit writes have already been
+// accepted within the trailing window, across every application and key.
+// Rejected writes do not count towards the limit.
+func RateLimitInterceptor(limit int, window time.Duration) WriteInterceptor {
+	var mu sync.Mutex
+	var events []time.Time
+
+	return func(ctx context.Context, action WriteAction, applicationName, configName, configValue string, next WriteNext) error {
+		mu.Lock()
+		cutoff := time.Now().Add(-window)
+		live := events[:0]
+		for _, t := range events {
+			if t.After(cutoff) {
+				live = append(live, t)
+			}
+		}
+		if len(live) >= limit {
+			events = live
+			mu.Unlock()
+			return fmt.Errorf("rate limit exceeded: more than %d writes within %s", limit, window)
+		}
+		events = append(live, time.Now())
+		mu.Unlock()
+
+		return next(ctx, action, applicationName, configName, configValue)
+	}
+}
@@ -0,0 +1,158 @@
+package configlite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func constReadInterceptor(suffix string) ReadInterceptor {
+	return func(ctx context.Context, applicationName, configName string, next ReadNext) (string, error) {
+		value, err := next(ctx, applicationName, configName)
+		if err != nil {
+			return "", err
+		}
+		return value + suffix, nil
+	}
+}
+
+func constWriteInterceptor(suffix string) WriteInterceptor {
+	return func(ctx context.Context, action WriteAction, applicationName, configName, configValue string, next WriteNext) error {
+		return next(ctx, action, applicationName, configName, configValue+suffix)
+	}
+}
+
+func TestChainReadRunsInRegistrationOrder(t *testing.T) {
+	terminal := func(ctx context.Context, applicationName, configName string) (string, error) {
+		return "base", nil
+	}
+	chain := chainRead([]ReadInterceptor{constReadInterceptor("-a"), constReadInterceptor("-b")}, terminal)
+
+	value, err := chain(context.Background(), "app1", "key1")
+	if err != nil {
+		t.Fatalf("cannot run read chain: %s", err)
+	}
+	// The first-registered interceptor wraps the second, so it sees (and
+	// appends after) the second interceptor's result last.
+	if value != "base-b-a" {
+		t.Fatalf("expected %q, got %q", "base-b-a", value)
+	}
+}
+
+func TestChainWriteRunsInRegistrationOrder(t *testing.T) {
+	var got string
+	terminal := func(ctx context.Context, action WriteAction, applicationName, configName, configValue string) error {
+		got = configValue
+		return nil
+	}
+	chain := chainWrite([]WriteInterceptor{constWriteInterceptor("-a"), constWriteInterceptor("-b")}, terminal)
+
+	if err := chain(context.Background(), WriteActionUpsert, "app1", "key1", "base"); err != nil {
+		t.Fatalf("cannot run write chain: %s", err)
+	}
+	if got != "base-a-b" {
+		t.Fatalf("expected %q, got %q", "base-a-b", got)
+	}
+}
+
+func TestChainReadShortCircuitsOnError(t *testing.T) {
+	terminal := func(ctx context.Context, applicationName, configName string) (string, error) {
+		t.Fatalf("terminal must not be reached when an earlier interceptor errors")
+		return "", nil
+	}
+	rejecting := func(ctx context.Context, applicationName, configName string, next ReadNext) (string, error) {
+		return "", fmt.Errorf("rejected")
+	}
+	chain := chainRead([]ReadInterceptor{rejecting, constReadInterceptor("-a")}, terminal)
+
+	if _, err := chain(context.Background(), "app1", "key1"); err == nil {
+		t.Fatalf("expected the chain to propagate the rejecting interceptor's error")
+	}
+}
+
+func TestWithCallerRoundTrip(t *testing.T) {
+	ctx := WithCaller(context.Background(), "alice")
+	caller, ok := CallerFromContext(ctx)
+	if !ok || caller != "alice" {
+		t.Fatalf("expected caller %q, got %q (ok=%v)", "alice", caller, ok)
+	}
+
+	if _, ok := CallerFromContext(context.Background()); ok {
+		t.Fatalf("expected no caller on a bare context")
+	}
+}
+
+func TestEnvExpansionInterceptorResolvesEnvAndCrossReference(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	t.Setenv("CONFIGLITE_TEST_VAR", "env-value")
+
+	if err := repo.UpsertConfig("app1", "base", "base-value"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+	if err := repo.UpsertConfig("app1", "derived",
+		"${CONFIGLITE_TEST_VAR}/${app1.base}/${UNSET_VAR}"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+
+	repo.UseReadInterceptor(EnvExpansionInterceptor(repo))
+
+	value, err := repo.GetConfig("app1", "derived")
+	if err != nil {
+		t.Fatalf("cannot get config: %s", err)
+	}
+	want := "env-value/base-value/${UNSET_VAR}"
+	if value != want {
+		t.Fatalf("expected %q, got %q", want, value)
+	}
+}
+
+func TestSizeLimitInterceptorRejectsOversizedValues(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	repo.UseWriteInterceptor(SizeLimitInterceptor(4))
+
+	if err := repo.UpsertConfig("app1", "key1", "12345"); err == nil {
+		t.Fatalf("expected an error for a value over the size limit")
+	}
+	if err := repo.UpsertConfig("app1", "key1", "1234"); err != nil {
+		t.Fatalf("expected a value at the size limit to be accepted, got: %s", err)
+	}
+}
+
+func TestAuditLogInterceptorRecordsWrites(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	repo.UseWriteInterceptor(AuditLogInterceptor(repo))
+
+	ctx := WithCaller(context.Background(), "alice")
+	if err := repo.UpsertConfig("app1", "key1", "value1", WithContext(ctx)); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+
+	var caller, action string
+	if err := repo.db.QueryRow(
+		`SELECT caller, action FROM audit_log WHERE application_name = ? AND configuration_name = ?`,
+		"app1", "key1",
+	).Scan(&caller, &action); err != nil {
+		t.Fatalf("cannot read audit log entry: %s", err)
+	}
+	if caller != "alice" {
+		t.Fatalf("expected caller %q, got %q", "alice", caller)
+	}
+	if action != string(WriteActionUpsert) {
+		t.Fatalf("expected action %q, got %q", WriteActionUpsert, action)
+	}
+}
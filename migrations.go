@@ -0,0 +1,123 @@
+package configlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/GuiaBolso/darwin"
+)
+
+// migrations lists, in order, every schema change ever applied to the
+// configuration database. Entries must never be edited or reordered once
+// released; new changes are appended with the next version number.
+var migrations = []darwin.Migration{
+	{
+		Version:     1,
+		Description: "Create applications table",
+		Script: `
+			CREATE TABLE applications (
+				name TEXT PRIMARY KEY
+			);`,
+	},
+	{
+		Version:     2,
+		Description: "Create configurations table",
+		Script: `
+			CREATE TABLE configurations (
+				application_name    TEXT NOT NULL REFERENCES applications(name),
+				configuration_name  TEXT NOT NULL,
+				configuration_value TEXT NOT NULL,
+				PRIMARY KEY (application_name, configuration_name)
+			);`,
+	},
+	{
+		Version:     3,
+		Description: "Create schemas and schema_versions tables",
+		Script: `
+			CREATE TABLE schemas (
+				application_name TEXT PRIMARY KEY REFERENCES applications(name),
+				version          INTEGER NOT NULL,
+				fields           TEXT NOT NULL
+			);
+			CREATE TABLE schema_versions (
+				application_name TEXT NOT NULL REFERENCES applications(name),
+				version          INTEGER NOT NULL,
+				fields           TEXT NOT NULL,
+				created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (application_name, version)
+			);`,
+	},
+	{
+		Version:     4,
+		Description: "Add encryption support for secret configuration values",
+		Script: `
+			CREATE TABLE encryption_keys (
+				key_id     TEXT PRIMARY KEY,
+				backend    TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			ALTER TABLE configurations ADD COLUMN configuration_encrypted BLOB;
+			ALTER TABLE configurations ADD COLUMN key_id TEXT REFERENCES encryption_keys(key_id);`,
+	},
+	{
+		Version:     5,
+		Description: "Track per-key change versions for external writers",
+		Script: `
+			CREATE TABLE config_changes (
+				application_name   TEXT NOT NULL,
+				configuration_name TEXT NOT NULL,
+				version            INTEGER NOT NULL,
+				updated_at         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (application_name, configuration_name)
+			);
+			CREATE TRIGGER config_changes_on_insert AFTER INSERT ON configurations
+			BEGIN
+				INSERT INTO config_changes (application_name, configuration_name, version)
+				VALUES (NEW.application_name, NEW.configuration_name,
+					(SELECT COALESCE(MAX(version), 0) + 1 FROM config_changes))
+				ON CONFLICT (application_name, configuration_name) DO UPDATE SET
+					version = excluded.version,
+					updated_at = CURRENT_TIMESTAMP;
+			END;
+			CREATE TRIGGER config_changes_on_update AFTER UPDATE ON configurations
+			BEGIN
+				INSERT INTO config_changes (application_name, configuration_name, version)
+				VALUES (NEW.application_name, NEW.configuration_name,
+					(SELECT COALESCE(MAX(version), 0) + 1 FROM config_changes))
+				ON CONFLICT (application_name, configuration_name) DO UPDATE SET
+					version = excluded.version,
+					updated_at = CURRENT_TIMESTAMP;
+			END;
+			CREATE TRIGGER config_changes_on_delete AFTER DELETE ON configurations
+			BEGIN
+				INSERT INTO config_changes (application_name, configuration_name, version)
+				VALUES (OLD.application_name, OLD.configuration_name,
+					(SELECT COALESCE(MAX(version), 0) + 1 FROM config_changes))
+				ON CONFLICT (application_name, configuration_name) DO UPDATE SET
+					version = excluded.version,
+					updated_at = CURRENT_TIMESTAMP;
+			END;`,
+	},
+	{
+		Version:     6,
+		Description: "Create audit_log table for write interceptors",
+		Script: `
+			CREATE TABLE audit_log (
+				id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+				caller             TEXT,
+				application_name   TEXT NOT NULL,
+				configuration_name TEXT NOT NULL,
+				action             TEXT NOT NULL,
+				occurred_at        TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);`,
+	},
+}
+
+func runMigrations(db *sql.DB) error {
+	driver := darwin.NewGenericDriver(db, darwin.SqliteDialect{})
+	d := darwin.New(driver, migrations, nil)
+	if err := d.Migrate(); err != nil {
+		return fmt.Errorf("cannot apply database migrations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,472 @@
+package configlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// FieldType enumerates the value types a schema field can declare.
+type FieldType string
+
+const (
+	FieldTypeString   FieldType = "string"
+	FieldTypeInt      FieldType = "int"
+	FieldTypeBool     FieldType = "bool"
+	FieldTypeFloat    FieldType = "float"
+	FieldTypeJSON     FieldType = "json"
+	FieldTypeDuration FieldType = "duration"
+)
+
+// MigrationFunc transforms the configuration values of an application from
+// one schema version to the next. It is invoked inside the same transaction
+// that bumps the stored schema version, so a failing migration leaves the
+// database untouched.
+type MigrationFunc func(old map[string]string) (map[string]string, error)
+
+// Field describes a single configuration key governed by a Schema.
+type Field struct {
+	Name     string
+	Type     FieldType
+	Default  string
+	Required bool
+	Enum     []string
+	Pattern  string
+	Min      *float64
+	Max      *float64
+}
+
+// Schema is the typed, versioned description of every configuration key an
+// application expects to read or write through GetTyped/UpsertTyped.
+type Schema struct {
+	Version int
+	Fields  []Field
+	// Migrate, when set, converts the configuration values stored under the
+	// previous schema version to values valid under Version. It is ignored
+	// when an application registers its first schema.
+	Migrate MigrationFunc
+}
+
+// ValidationError reports a single configuration value violating its
+// application's registered schema.
+type ValidationError struct {
+	Application string
+	Key         string
+	Reason      string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s.%s: %s", e.Application, e.Key, e.Reason)
+}
+
+var ErrSchemaNotFound = fmt.Errorf("schema not found")
+
+func (f Field) validate(value string) error {
+	switch f.Type {
+	case FieldTypeInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%q is not a valid int: %w", value, err)
+		}
+	case FieldTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a valid bool: %w", value, err)
+		}
+	case FieldTypeFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a valid float: %w", value, err)
+		}
+	case FieldTypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("%q is not a valid duration: %w", value, err)
+		}
+	case FieldTypeJSON:
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("%q is not valid json", value)
+		}
+	case FieldTypeString:
+		// any value is a valid string
+	default:
+		return fmt.Errorf("unknown field type %q", f.Type)
+	}
+
+	if len(f.Enum) > 0 {
+		found := false
+		for _, e := range f.Enum {
+			if e == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%q is not one of %v", value, f.Enum)
+		}
+	}
+
+	if f.Pattern != "" {
+		re, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid validation pattern %q: %w", f.Pattern, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q does not match pattern %q", value, f.Pattern)
+		}
+	}
+
+	if f.Min != nil || f.Max != nil {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a comparable number for range validation: %w", value, err)
+		}
+		if f.Min != nil && n < *f.Min {
+			return fmt.Errorf("%v is below the minimum %v", n, *f.Min)
+		}
+		if f.Max != nil && n > *f.Max {
+			return fmt.Errorf("%v is above the maximum %v", n, *f.Max)
+		}
+	}
+
+	return nil
+}
+
+func marshalFields(fields []Field) (string, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal schema fields: %w", err)
+	}
+	return string(b), nil
+}
+
+func unmarshalFields(raw string) ([]Field, error) {
+	var fields []Field
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal schema fields: %w", err)
+	}
+	return fields, nil
+}
+
+// RegisterSchema declares or updates the typed schema for an application. A
+// first-time registration simply records the schema. Registering a new,
+// higher Version over an existing schema runs schema.Migrate, if set, over
+// the application's current configuration values and persists the result,
+// all inside a single transaction. Registering the same Version again (the
+// expected call on every application startup, not only the first) is a
+// no-op; registering an older Version than the one on record is an error.
+func (r *Repository) RegisterSchema(app string, schema Schema) error {
+	fieldsJSON, err := marshalFields(schema.Fields)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("cannot start transaction to register schema: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`INSERT INTO applications (name) VALUES (?) ON CONFLICT DO NOTHING`, app,
+	); err != nil {
+		return fmt.Errorf("cannot register application %s: %w", app, err)
+	}
+
+	var currentVersion int
+	var currentFieldsJSON string
+	err = tx.QueryRow(
+		`SELECT version, fields FROM schemas WHERE application_name = ?`, app,
+	).Scan(&currentVersion, &currentFieldsJSON)
+
+	switch {
+	case err == sql.ErrNoRows:
+		if _, err := tx.Exec(
+			`INSERT INTO schemas (application_name, version, fields) VALUES (?, ?, ?)`,
+			app, schema.Version, fieldsJSON); err != nil {
+			return fmt.Errorf("cannot insert schema for %s: %w", app, err)
+		}
+	case err != nil:
+		return fmt.Errorf("cannot look up current schema for %s: %w", app, err)
+	case schema.Version == currentVersion:
+		// A no-op, not an error: the expected call pattern is for an
+		// application to RegisterSchema its current version on every
+		// startup, not only the first time.
+		return tx.Commit()
+	case schema.Version < currentVersion:
+		return fmt.Errorf(
+			"schema version %d for %s is older than the registered version %d",
+			schema.Version, app, currentVersion)
+	default:
+		if schema.Migrate != nil {
+			oldConfigs, err := getConfigs(tx, app)
+			if err != nil {
+				return fmt.Errorf("cannot load configs for %s prior to migration: %w", app, err)
+			}
+			newConfigs, err := schema.Migrate(oldConfigs)
+			if err != nil {
+				return fmt.Errorf("schema migration for %s to version %d failed: %w",
+					app, schema.Version, err)
+			}
+			for k, v := range newConfigs {
+				if _, err := tx.Exec(`
+					INSERT INTO configurations (application_name, configuration_name, configuration_value)
+					VALUES (?1, ?2, ?3)
+					ON CONFLICT (application_name, configuration_name) DO
+					UPDATE SET configuration_value = ?3`, app, k, v); err != nil {
+					return fmt.Errorf("cannot persist migrated config %s.%s: %w", app, k, err)
+				}
+			}
+		}
+
+		if _, err := tx.Exec(
+			`UPDATE schemas SET version = ?, fields = ? WHERE application_name = ?`,
+			schema.Version, fieldsJSON, app); err != nil {
+			return fmt.Errorf("cannot update schema for %s: %w", app, err)
+		}
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO schema_versions (application_name, version, fields) VALUES (?, ?, ?)
+			ON CONFLICT DO NOTHING`,
+		app, schema.Version, fieldsJSON); err != nil {
+		return fmt.Errorf("cannot record schema version %d for %s: %w", schema.Version, app, err)
+	}
+
+	return tx.Commit()
+}
+
+func getConfigs(q interface {
+	Query(string, ...any) (*sql.Rows, error)
+}, app string) (map[string]string, error) {
+	rows, err := q.Query(`
+		SELECT configuration_name, configuration_value
+		FROM configurations
+		WHERE application_name = ?`, app)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query configurations for %s: %w", app, err)
+	}
+	defer rows.Close()
+
+	configs := map[string]string{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("cannot scan config row for %s: %w", app, err)
+		}
+		configs[name] = value
+	}
+	return configs, rows.Err()
+}
+
+func (r *Repository) getSchema(app string) (Schema, error) {
+	var version int
+	var fieldsJSON string
+	err := r.db.QueryRow(
+		`SELECT version, fields FROM schemas WHERE application_name = ?`, app,
+	).Scan(&version, &fieldsJSON)
+	if err == sql.ErrNoRows {
+		return Schema{}, fmt.Errorf("%w: %s", ErrSchemaNotFound, app)
+	}
+	if err != nil {
+		return Schema{}, fmt.Errorf("cannot look up schema for %s: %w", app, err)
+	}
+
+	fields, err := unmarshalFields(fieldsJSON)
+	if err != nil {
+		return Schema{}, err
+	}
+	return Schema{Version: version, Fields: fields}, nil
+}
+
+func fieldByName(fields []Field, name string) (Field, bool) {
+	for _, f := range fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// GetTyped reads applicationName's key, validates it against the
+// application's registered schema and coerces it into out, which must be a
+// non-nil pointer of a type matching the field's declared type (*string,
+// *int64, *bool, *float64, *time.Duration, or any JSON-unmarshalable type
+// for FieldTypeJSON). If the value is absent, the field's default is used.
+// If there is no value and no default: a required field is an error, and an
+// optional field leaves out untouched, for every field type, so callers can
+// rely on out keeping its zero or pre-set value rather than receiving a
+// type-specific parse error from an empty string.
+func (r *Repository) GetTyped(app, key string, out any) error {
+	schema, err := r.getSchema(app)
+	if err != nil {
+		return err
+	}
+
+	field, ok := fieldByName(schema.Fields, key)
+	if !ok {
+		return fmt.Errorf("%s is not declared in the schema for %s", key, app)
+	}
+
+	value, err := r.GetConfig(app, key)
+	if err != nil {
+		if !errors.Is(err, ErrConfigNotFound) {
+			return err
+		}
+		if field.Default == "" {
+			if field.Required {
+				return fmt.Errorf("%s.%s is required but has no value and no default", app, key)
+			}
+			return nil
+		}
+		value = field.Default
+	}
+
+	return coerce(field.Type, value, out)
+}
+
+func coerce(fieldType FieldType, value string, out any) error {
+	switch fieldType {
+	case FieldTypeJSON:
+		if err := json.Unmarshal([]byte(value), out); err != nil {
+			return fmt.Errorf("cannot unmarshal json value %q: %w", value, err)
+		}
+		return nil
+	case FieldTypeDuration:
+		ptr, ok := out.(*time.Duration)
+		if !ok {
+			return fmt.Errorf("out must be *time.Duration for a duration field")
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("cannot parse duration %q: %w", value, err)
+		}
+		*ptr = d
+		return nil
+	}
+
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("out must be a non-nil pointer")
+	}
+	elem := rv.Elem()
+
+	switch fieldType {
+	case FieldTypeString:
+		if elem.Kind() != reflect.String {
+			return fmt.Errorf("out must be *string for a string field")
+		}
+		elem.SetString(value)
+	case FieldTypeInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse int %q: %w", value, err)
+		}
+		if elem.Kind() < reflect.Int || elem.Kind() > reflect.Int64 {
+			return fmt.Errorf("out must be an *int/*int64 for an int field")
+		}
+		elem.SetInt(n)
+	case FieldTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("cannot parse bool %q: %w", value, err)
+		}
+		if elem.Kind() != reflect.Bool {
+			return fmt.Errorf("out must be *bool for a bool field")
+		}
+		elem.SetBool(b)
+	case FieldTypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse float %q: %w", value, err)
+		}
+		if elem.Kind() != reflect.Float32 && elem.Kind() != reflect.Float64 {
+			return fmt.Errorf("out must be a *float32/*float64 for a float field")
+		}
+		elem.SetFloat(f)
+	default:
+		return fmt.Errorf("unknown field type %q", fieldType)
+	}
+	return nil
+}
+
+func render(fieldType FieldType, v any) (string, error) {
+	if fieldType == FieldTypeJSON {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("cannot marshal json value: %w", err)
+		}
+		return string(b), nil
+	}
+	if d, ok := v.(time.Duration); ok {
+		return d.String(), nil
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+// UpsertTyped validates v against applicationName's registered schema for
+// key and, if it passes, stores its string representation via UpsertConfig.
+// Writes that would violate the schema (wrong enum member, out of range,
+// pattern mismatch, ...) are rejected without touching the database.
+func (r *Repository) UpsertTyped(app, key string, v any) error {
+	schema, err := r.getSchema(app)
+	if err != nil {
+		return err
+	}
+
+	field, ok := fieldByName(schema.Fields, key)
+	if !ok {
+		return fmt.Errorf("%s is not declared in the schema for %s", key, app)
+	}
+
+	value, err := render(field.Type, v)
+	if err != nil {
+		return err
+	}
+
+	if err := field.validate(value); err != nil {
+		return fmt.Errorf("value for %s.%s violates its schema: %w", app, key, err)
+	}
+
+	return r.UpsertConfig(app, key, value)
+}
+
+// Validate walks every configuration value stored for app against its
+// registered schema and reports every missing-required, type-mismatch,
+// out-of-range and pattern violation it finds.
+func (r *Repository) Validate(app string) ([]ValidationError, error) {
+	schema, err := r.getSchema(app)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := r.GetConfigs(app)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []ValidationError
+	for _, field := range schema.Fields {
+		value, present := configs[field.Name]
+		if !present {
+			if field.Required && field.Default == "" {
+				violations = append(violations, ValidationError{
+					Application: app,
+					Key:         field.Name,
+					Reason:      "required value is missing and has no default",
+				})
+			}
+			continue
+		}
+		if err := field.validate(value); err != nil {
+			violations = append(violations, ValidationError{
+				Application: app,
+				Key:         field.Name,
+				Reason:      err.Error(),
+			})
+		}
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,221 @@
+package configlite
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterSchemaSameVersionIsNoOp(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	schema := Schema{Version: 1, Fields: []Field{{Name: "foo", Type: FieldTypeString}}}
+	if err := repo.RegisterSchema("app1", schema); err != nil {
+		t.Fatalf("cannot register schema: %s", err)
+	}
+
+	// An application re-registering its current schema version on every
+	// startup must not fail.
+	if err := repo.RegisterSchema("app1", schema); err != nil {
+		t.Fatalf("re-registering the same schema version should be a no-op, got: %s", err)
+	}
+}
+
+func TestRegisterSchemaRejectsOlderVersion(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	if err := repo.RegisterSchema("app1", Schema{Version: 2}); err != nil {
+		t.Fatalf("cannot register schema: %s", err)
+	}
+
+	if err := repo.RegisterSchema("app1", Schema{Version: 1}); err == nil {
+		t.Fatalf("expected an error registering an older schema version")
+	}
+}
+
+func TestRegisterSchemaMigratesOnVersionBump(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	if err := repo.RegisterSchema("app1", Schema{Version: 1}); err != nil {
+		t.Fatalf("cannot register schema: %s", err)
+	}
+	if err := repo.UpsertConfig("app1", "foo", "1"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+
+	migrated := Schema{
+		Version: 2,
+		Fields:  []Field{{Name: "foo", Type: FieldTypeString}},
+		Migrate: func(old map[string]string) (map[string]string, error) {
+			return map[string]string{"foo": old["foo"] + "-migrated"}, nil
+		},
+	}
+	if err := repo.RegisterSchema("app1", migrated); err != nil {
+		t.Fatalf("cannot register migrated schema: %s", err)
+	}
+
+	value, err := repo.GetConfig("app1", "foo")
+	if err != nil {
+		t.Fatalf("cannot get config: %s", err)
+	}
+	if value != "1-migrated" {
+		t.Fatalf("expected migrated value %q, got %q", "1-migrated", value)
+	}
+}
+
+func TestGetTypedAppliesDefaults(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	schema := Schema{Fields: []Field{
+		{Name: "count", Type: FieldTypeInt, Default: "3"},
+		{Name: "enabled", Type: FieldTypeBool, Default: "true"},
+		{Name: "timeout", Type: FieldTypeDuration, Default: "5s"},
+	}}
+	if err := repo.RegisterSchema("app1", schema); err != nil {
+		t.Fatalf("cannot register schema: %s", err)
+	}
+
+	var count int64
+	if err := repo.GetTyped("app1", "count", &count); err != nil {
+		t.Fatalf("cannot get typed count: %s", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected default count 3, got %d", count)
+	}
+
+	var enabled bool
+	if err := repo.GetTyped("app1", "enabled", &enabled); err != nil {
+		t.Fatalf("cannot get typed enabled: %s", err)
+	}
+	if !enabled {
+		t.Fatalf("expected default enabled true, got false")
+	}
+
+	var timeout time.Duration
+	if err := repo.GetTyped("app1", "timeout", &timeout); err != nil {
+		t.Fatalf("cannot get typed timeout: %s", err)
+	}
+	if timeout != 5*time.Second {
+		t.Fatalf("expected default timeout 5s, got %s", timeout)
+	}
+}
+
+func TestGetTypedRequiredWithNoValueOrDefaultErrors(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	schema := Schema{Fields: []Field{{Name: "count", Type: FieldTypeInt, Required: true}}}
+	if err := repo.RegisterSchema("app1", schema); err != nil {
+		t.Fatalf("cannot register schema: %s", err)
+	}
+
+	var count int64
+	err = repo.GetTyped("app1", "count", &count)
+	if err == nil {
+		t.Fatalf("expected an error for a required field with no value and no default")
+	}
+	if !strings.Contains(err.Error(), "required") {
+		t.Fatalf("expected a clear required-field error, got: %s", err)
+	}
+}
+
+// TestGetTypedOptionalWithNoValueOrDefaultLeavesOutUntouched covers every
+// non-string field type: with no stored value and no default, GetTyped must
+// leave out untouched rather than failing to parse an empty string.
+func TestGetTypedOptionalWithNoValueOrDefaultLeavesOutUntouched(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	schema := Schema{Fields: []Field{
+		{Name: "count", Type: FieldTypeInt},
+		{Name: "enabled", Type: FieldTypeBool},
+		{Name: "ratio", Type: FieldTypeFloat},
+		{Name: "timeout", Type: FieldTypeDuration},
+		{Name: "payload", Type: FieldTypeJSON},
+	}}
+	if err := repo.RegisterSchema("app1", schema); err != nil {
+		t.Fatalf("cannot register schema: %s", err)
+	}
+
+	count := int64(42)
+	if err := repo.GetTyped("app1", "count", &count); err != nil {
+		t.Fatalf("expected no error for an optional int field, got: %s", err)
+	}
+	if count != 42 {
+		t.Fatalf("expected out to be left untouched at 42, got %d", count)
+	}
+
+	enabled := true
+	if err := repo.GetTyped("app1", "enabled", &enabled); err != nil {
+		t.Fatalf("expected no error for an optional bool field, got: %s", err)
+	}
+	if !enabled {
+		t.Fatalf("expected out to be left untouched at true, got false")
+	}
+
+	ratio := 1.5
+	if err := repo.GetTyped("app1", "ratio", &ratio); err != nil {
+		t.Fatalf("expected no error for an optional float field, got: %s", err)
+	}
+	if ratio != 1.5 {
+		t.Fatalf("expected out to be left untouched at 1.5, got %v", ratio)
+	}
+
+	timeout := 2 * time.Second
+	if err := repo.GetTyped("app1", "timeout", &timeout); err != nil {
+		t.Fatalf("expected no error for an optional duration field, got: %s", err)
+	}
+	if timeout != 2*time.Second {
+		t.Fatalf("expected out to be left untouched at 2s, got %s", timeout)
+	}
+
+	payload := map[string]int{"a": 1}
+	if err := repo.GetTyped("app1", "payload", &payload); err != nil {
+		t.Fatalf("expected no error for an optional json field, got: %s", err)
+	}
+	if len(payload) != 1 || payload["a"] != 1 {
+		t.Fatalf("expected out to be left untouched, got %v", payload)
+	}
+}
+
+func TestUpsertTypedRejectsInvalidValue(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	schema := Schema{Fields: []Field{{Name: "env", Type: FieldTypeString, Enum: []string{"dev", "prod"}}}}
+	if err := repo.RegisterSchema("app1", schema); err != nil {
+		t.Fatalf("cannot register schema: %s", err)
+	}
+
+	if err := repo.UpsertTyped("app1", "env", "staging"); err == nil {
+		t.Fatalf("expected an error for a value outside the declared enum")
+	}
+	if err := repo.UpsertTyped("app1", "env", "prod"); err != nil {
+		t.Fatalf("cannot upsert a valid enum value: %s", err)
+	}
+}
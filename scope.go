@@ -0,0 +1,93 @@
+package configlite
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ResolvedValue is one entry of a GetEffectiveConfigs result: the value
+// itself, and the scope that supplied it.
+type ResolvedValue struct {
+	Value string
+	Scope string
+}
+
+// scopeAncestors returns scope and every one of its ancestor scopes, from
+// scope itself (most specific) down to its root application (least
+// specific). A scope with no "/" has itself as its only ancestor.
+func scopeAncestors(scope string) []string {
+	segments := strings.Split(scope, "/")
+	ancestors := make([]string, len(segments))
+	for i := range segments {
+		ancestors[i] = strings.Join(segments[:len(segments)-i], "/")
+	}
+	return ancestors
+}
+
+// resolutionScopes returns the ordered list of application names ResolveConfig
+// and GetEffectiveConfigs consult for scope, most specific first: scope and
+// its ancestors, with profile (if set) overlaid one level above the root
+// application, below every other ancestor.
+func resolutionScopes(scope, profile string) []string {
+	ancestors := scopeAncestors(scope)
+	if profile == "" {
+		return ancestors
+	}
+
+	root := ancestors[len(ancestors)-1]
+	profileScope := root + "/" + profile
+
+	scopes := append([]string{}, ancestors[:len(ancestors)-1]...)
+	for _, s := range scopes {
+		if s == profileScope {
+			return ancestors
+		}
+	}
+	scopes = append(scopes, profileScope, root)
+	return scopes
+}
+
+// ResolveConfig looks up key starting at scope and walking up through its
+// ancestor scopes (app/prod/host-a, then app/prod, then app), returning the
+// value found at the most specific scope. When profile is set via
+// WithProfile, the overlay scope "<root>/<profile>" is consulted between
+// scope's own ancestors and the root application, letting a single database
+// hold per-profile variants without duplicating every key at every scope.
+func (r *Repository) ResolveConfig(scope, key string, opts ...ConfigOption) (string, error) {
+	options := applyConfigOptions(opts)
+
+	for _, candidate := range resolutionScopes(scope, options.profile) {
+		value, err := r.GetConfig(candidate, key, opts...)
+		if err == nil {
+			return value, nil
+		}
+		if !errors.Is(err, ErrConfigNotFound) {
+			return "", fmt.Errorf("cannot resolve %s at scope %s: %w", key, candidate, err)
+		}
+	}
+
+	return "", fmt.Errorf("%w: scope %s, key %s", ErrConfigNotFound, scope, key)
+}
+
+// GetEffectiveConfigs returns the merged configuration visible from scope:
+// every key registered anywhere along scope's ancestor chain (and its
+// profile overlay, if any), each carrying the scope that supplied its value.
+// A more specific scope's value always wins over a less specific one.
+func (r *Repository) GetEffectiveConfigs(scope string, opts ...ConfigOption) (map[string]ResolvedValue, error) {
+	options := applyConfigOptions(opts)
+
+	scopes := resolutionScopes(scope, options.profile)
+	effective := map[string]ResolvedValue{}
+	for i := len(scopes) - 1; i >= 0; i-- {
+		s := scopes[i]
+		configs, err := r.GetConfigs(s, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get configs for scope %s: %w", s, err)
+		}
+		for key, value := range configs {
+			effective[key] = ResolvedValue{Value: value, Scope: s}
+		}
+	}
+	return effective, nil
+}
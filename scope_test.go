@@ -0,0 +1,125 @@
+package configlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScopeAncestors(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  []string
+	}{
+		{"app", []string{"app"}},
+		{"app/prod", []string{"app/prod", "app"}},
+		{"app/prod/host-a", []string{"app/prod/host-a", "app/prod", "app"}},
+	}
+	for _, tt := range tests {
+		got := scopeAncestors(tt.scope)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("scopeAncestors(%q) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestResolutionScopesWithoutProfile(t *testing.T) {
+	got := resolutionScopes("app/prod/host-a", "")
+	want := []string{"app/prod/host-a", "app/prod", "app"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resolutionScopes = %v, want %v", got, want)
+	}
+}
+
+func TestResolutionScopesOverlaysProfileAboveRoot(t *testing.T) {
+	got := resolutionScopes("app/prod/host-a", "canary")
+	want := []string{"app/prod/host-a", "app/prod", "app/canary", "app"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resolutionScopes = %v, want %v", got, want)
+	}
+}
+
+func TestResolutionScopesSkipsDuplicateProfileOverlay(t *testing.T) {
+	// The scope chain already passes through "app/canary" as an ordinary
+	// ancestor, so the profile overlay must not be inserted a second time.
+	got := resolutionScopes("app/canary/host-a", "canary")
+	want := []string{"app/canary/host-a", "app/canary", "app"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resolutionScopes = %v, want %v", got, want)
+	}
+}
+
+func TestResolveConfigWalksFromMostToLeastSpecific(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	if err := repo.UpsertConfig("app", "timeout", "30s"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+	if err := repo.UpsertConfig("app/prod", "timeout", "10s"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+
+	value, err := repo.ResolveConfig("app/prod/host-a", "timeout")
+	if err != nil {
+		t.Fatalf("cannot resolve config: %s", err)
+	}
+	if value != "10s" {
+		t.Fatalf("expected the more specific scope's value %q, got %q", "10s", value)
+	}
+
+	if err := repo.UpsertConfig("app/prod/host-a", "timeout", "1s"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+	value, err = repo.ResolveConfig("app/prod/host-a", "timeout")
+	if err != nil {
+		t.Fatalf("cannot resolve config: %s", err)
+	}
+	if value != "1s" {
+		t.Fatalf("expected the most specific scope's value %q, got %q", "1s", value)
+	}
+}
+
+func TestResolveConfigNotFoundAcrossEveryAncestor(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	if _, err := repo.ResolveConfig("app/prod/host-a", "missing"); err == nil {
+		t.Fatalf("expected ErrConfigNotFound when no ancestor scope has the key")
+	}
+}
+
+func TestGetEffectiveConfigsMergesWithMoreSpecificWinning(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	if err := repo.UpsertConfig("app", "timeout", "30s"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+	if err := repo.UpsertConfig("app", "region", "us-east"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+	if err := repo.UpsertConfig("app/prod", "timeout", "10s"); err != nil {
+		t.Fatalf("cannot upsert config: %s", err)
+	}
+
+	effective, err := repo.GetEffectiveConfigs("app/prod/host-a")
+	if err != nil {
+		t.Fatalf("cannot get effective configs: %s", err)
+	}
+
+	if got := effective["timeout"]; got.Value != "10s" || got.Scope != "app/prod" {
+		t.Fatalf("expected timeout=10s from app/prod, got %+v", got)
+	}
+	if got := effective["region"]; got.Value != "us-east" || got.Scope != "app" {
+		t.Fatalf("expected region=us-east from app, got %+v", got)
+	}
+}
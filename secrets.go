@@ -0,0 +1,123 @@
+package configlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// RedactedSecret is returned by GetConfig/GetConfigs in place of an
+// encrypted value when the caller did not supply a Decrypter. A supplied
+// Decrypter that fails to unwrap the value makes the read return an error.
+const RedactedSecret = "***REDACTED***"
+
+// Cipher encrypts and decrypts secret configuration values for a given
+// key ID. Encrypt/Decrypt implementations are free to ignore keyID when a
+// single cipher instance only ever handles one key.
+type Cipher interface {
+	Encrypt(plaintext []byte, keyID string) ([]byte, error)
+	Decrypter
+}
+
+// Decrypter is the read side of Cipher. GetConfig and GetConfigs accept one
+// through WithDecrypter to transparently decrypt secret values; without one,
+// secrets are returned as RedactedSecret.
+type Decrypter interface {
+	Decrypt(ciphertext []byte, keyID string) ([]byte, error)
+}
+
+// ConfigOption customizes a GetConfig/GetConfigs call.
+type ConfigOption func(*configOptions)
+
+type configOptions struct {
+	decrypter Decrypter
+	ctx       context.Context
+	profile   string
+}
+
+// WithDecrypter makes GetConfig/GetConfigs decrypt secret values using d
+// instead of returning RedactedSecret for them.
+func WithDecrypter(d Decrypter) ConfigOption {
+	return func(o *configOptions) { o.decrypter = d }
+}
+
+// WithContext makes GetConfig/GetConfigs/UpsertConfig/DeleteConfig run their
+// read/write interceptor chain with ctx instead of context.Background(). Use
+// WithCaller to attach a caller identity that AuditLogInterceptor can record.
+func WithContext(ctx context.Context) ConfigOption {
+	return func(o *configOptions) { o.ctx = ctx }
+}
+
+// WithProfile makes ResolveConfig/GetEffectiveConfigs additionally consult
+// the overlay scope "<root>/<profile>", so a single database can hold
+// dev/staging/prod variants of a key without duplicating it at every scope.
+func WithProfile(profile string) ConfigOption {
+	return func(o *configOptions) { o.profile = profile }
+}
+
+func applyConfigOptions(opts []ConfigOption) configOptions {
+	options := configOptions{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func resolveSecret(value string, encrypted []byte, keyID sql.NullString, options configOptions) (string, error) {
+	if encrypted == nil {
+		return value, nil
+	}
+	if options.decrypter == nil {
+		return RedactedSecret, nil
+	}
+	plaintext, err := options.decrypter.Decrypt(encrypted, keyID.String)
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt secret (key %s): %w", keyID.String, err)
+	}
+	return string(plaintext), nil
+}
+
+// RegisterEncryptionKey records keyID as encrypted with the given backend
+// label and makes cipher the active key used by subsequent UpsertSecret
+// calls on this Repository.
+func (r *Repository) RegisterEncryptionKey(keyID, backend string, cipher Cipher) error {
+	if _, err := r.db.Exec(
+		`INSERT INTO encryption_keys (key_id, backend) VALUES (?, ?) ON CONFLICT DO NOTHING`,
+		keyID, backend,
+	); err != nil {
+		return fmt.Errorf("cannot register encryption key %s: %w", keyID, err)
+	}
+
+	r.activeKeyID = keyID
+	r.activeCipher = cipher
+	return nil
+}
+
+// UpsertSecret encrypts plaintext with the Repository's active encryption
+// key, registered via RegisterEncryptionKey, and stores the ciphertext for
+// (applicationName, configName). Reads of this value through GetConfig or
+// GetConfigs return RedactedSecret unless the caller supplies a Decrypter
+// via WithDecrypter; a Decrypter that cannot unwrap it makes the read fail.
+func (r *Repository) UpsertSecret(applicationName, configName, plaintext string) error {
+	if r.activeCipher == nil {
+		return fmt.Errorf("cannot upsert secret (%s, %s): no encryption key registered",
+			applicationName, configName)
+	}
+
+	ciphertext, err := r.activeCipher.Encrypt([]byte(plaintext), r.activeKeyID)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt secret (%s, %s): %w", applicationName, configName, err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO configurations
+			(application_name, configuration_name, configuration_value, configuration_encrypted, key_id)
+		VALUES (?1, ?2, '', ?3, ?4)
+		ON CONFLICT (application_name, configuration_name) DO
+		UPDATE SET configuration_value = '', configuration_encrypted = ?3, key_id = ?4`,
+		applicationName, configName, ciphertext, r.activeKeyID)
+	if err != nil {
+		return fmt.Errorf("cannot store secret (%s, %s): %w", applicationName, configName, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package configlite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestAESKeyfileCipherRoundTrip(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "key")
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	if err := os.WriteFile(keyPath, key, 0o600); err != nil {
+		t.Fatalf("cannot write key file: %s", err)
+	}
+
+	c, err := NewAESKeyfileCipher(keyPath)
+	if err != nil {
+		t.Fatalf("cannot build AES cipher: %s", err)
+	}
+
+	ciphertext, err := c.Encrypt([]byte("hunter2"), "")
+	if err != nil {
+		t.Fatalf("cannot encrypt: %s", err)
+	}
+	if string(ciphertext) == "hunter2" {
+		t.Fatalf("ciphertext must not equal the plaintext")
+	}
+
+	plaintext, err := c.Decrypt(ciphertext, "")
+	if err != nil {
+		t.Fatalf("cannot decrypt: %s", err)
+	}
+	if string(plaintext) != "hunter2" {
+		t.Fatalf("expected decrypted plaintext %q, got %q", "hunter2", plaintext)
+	}
+}
+
+func TestAESKeyfileCipherRejectsWrongLengthKey(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyPath, []byte("too-short"), 0o600); err != nil {
+		t.Fatalf("cannot write key file: %s", err)
+	}
+
+	if _, err := NewAESKeyfileCipher(keyPath); err == nil {
+		t.Fatalf("expected an error for a key file that isn't 32 bytes")
+	}
+}
+
+func TestAgeCipherRoundTrip(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("cannot generate age identity: %s", err)
+	}
+
+	c, err := NewAgeCipher(identity.Recipient().String(), identity.String())
+	if err != nil {
+		t.Fatalf("cannot build age cipher: %s", err)
+	}
+
+	ciphertext, err := c.Encrypt([]byte("hunter2"), "")
+	if err != nil {
+		t.Fatalf("cannot encrypt: %s", err)
+	}
+
+	plaintext, err := c.Decrypt(ciphertext, "")
+	if err != nil {
+		t.Fatalf("cannot decrypt: %s", err)
+	}
+	if string(plaintext) != "hunter2" {
+		t.Fatalf("expected decrypted plaintext %q, got %q", "hunter2", plaintext)
+	}
+}
+
+func TestAgeCipherRequiresRecipientAndIdentity(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("cannot generate age identity: %s", err)
+	}
+
+	encryptOnly, err := NewAgeCipher(identity.Recipient().String(), "")
+	if err != nil {
+		t.Fatalf("cannot build encrypt-only age cipher: %s", err)
+	}
+	if _, err := encryptOnly.Decrypt(nil, ""); err == nil {
+		t.Fatalf("expected an error decrypting with no identity configured")
+	}
+
+	decryptOnly, err := NewAgeCipher("", identity.String())
+	if err != nil {
+		t.Fatalf("cannot build decrypt-only age cipher: %s", err)
+	}
+	if _, err := decryptOnly.Encrypt([]byte("x"), ""); err == nil {
+		t.Fatalf("expected an error encrypting with no recipient configured")
+	}
+}
+
+func TestUpsertSecretRedactsWithoutDecrypter(t *testing.T) {
+	repo, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("cannot open in-memory database: %s", err)
+	}
+	defer repo.Close()
+
+	keyPath := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyPath, make([]byte, 32), 0o600); err != nil {
+		t.Fatalf("cannot write key file: %s", err)
+	}
+	cipher, err := NewAESKeyfileCipher(keyPath)
+	if err != nil {
+		t.Fatalf("cannot build AES cipher: %s", err)
+	}
+	if err := repo.RegisterEncryptionKey("k1", "aes-keyfile", cipher); err != nil {
+		t.Fatalf("cannot register encryption key: %s", err)
+	}
+
+	if err := repo.UpsertSecret("app1", "password", "hunter2"); err != nil {
+		t.Fatalf("cannot upsert secret: %s", err)
+	}
+
+	redacted, err := repo.GetConfig("app1", "password")
+	if err != nil {
+		t.Fatalf("cannot get config: %s", err)
+	}
+	if redacted != RedactedSecret {
+		t.Fatalf("expected %q without a decrypter, got %q", RedactedSecret, redacted)
+	}
+
+	plaintext, err := repo.GetConfig("app1", "password", WithDecrypter(cipher))
+	if err != nil {
+		t.Fatalf("cannot get config with decrypter: %s", err)
+	}
+	if plaintext != "hunter2" {
+		t.Fatalf("expected decrypted value %q, got %q", "hunter2", plaintext)
+	}
+}